@@ -0,0 +1,265 @@
+package coleteonline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type AddressStore interface {
+	// Returns no addresses and no error when nothing was saved yet.
+	Load() ([]OrderAddress, error)
+	Save(addresses []OrderAddress) error
+}
+
+// Persists the addresses as a JSON array in the file at Path.
+type JSONFileAddressStore struct {
+	Path string
+}
+
+func (s *JSONFileAddressStore) Load() ([]OrderAddress, error) {
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var res []OrderAddress
+	err = json.Unmarshal(b, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// The file is replaced atomically so a failed save never leaves a partial file behind.
+func (s *JSONFileAddressStore) Save(addresses []OrderAddress) error {
+	b, err := json.MarshalIndent(addresses, "", "\t")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.Path, b)
+}
+
+// Writes to a temporary file in the same directory, then renames it over path.
+func writeFileAtomic(path string, b []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Close()
+	} else {
+		f.Close()
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	err = os.Rename(f.Name(), path)
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+type AddressBookChanges struct {
+	Added   []OrderAddress
+	Updated []OrderAddress
+	Removed []OrderAddress
+}
+
+func (c *AddressBookChanges) Empty() bool {
+	return len(c.Added) == 0 && len(c.Updated) == 0 && len(c.Removed) == 0
+}
+
+// AddressBook mirrors the saved addresses locally, indexed by id, contact name, company and postal code.
+type AddressBook struct {
+	client       *Client
+	store        AddressStore
+	mu           sync.RWMutex
+	addresses    []OrderAddress
+	byId         map[int64]int
+	byName       map[string][]int
+	byCompany    map[string][]int
+	byPostalCode map[string][]int
+}
+
+// The store is optional, addresses are only kept in memory when it is nil.
+func NewAddressBook(client *Client, store AddressStore) *AddressBook {
+	b := &AddressBook{
+		client: client,
+		store:  store,
+	}
+	b.index(nil)
+	return b
+}
+
+// Load reads the addresses from the store, without calling the API.
+func (b *AddressBook) Load() error {
+	if b.store == nil {
+		return nil
+	}
+	addresses, err := b.store.Load()
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.index(addresses)
+	return nil
+}
+
+// Resync is a full resync: it downloads every address page, since the API has no way to list
+// only the changes, then applies the differences to the book and saves it to the store when
+// anything changed. Its cost grows with the number of saved addresses.
+func (b *AddressBook) Resync(ctx context.Context) (*AddressBookChanges, error) {
+	addresses, err := b.client.AllAddresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	changes := &AddressBookChanges{}
+	seen := make(map[int64]bool, len(addresses))
+	for _, a := range addresses {
+		seen[a.AddressId] = true
+		i, ok := b.byId[a.AddressId]
+		if !ok {
+			changes.Added = append(changes.Added, a)
+		} else if !reflect.DeepEqual(b.addresses[i], a) {
+			changes.Updated = append(changes.Updated, a)
+		}
+	}
+	for _, a := range b.addresses {
+		if !seen[a.AddressId] {
+			changes.Removed = append(changes.Removed, a)
+		}
+	}
+	if changes.Empty() {
+		return changes, nil
+	}
+	if b.store != nil {
+		err = b.store.Save(addresses)
+		if err != nil {
+			return nil, err
+		}
+	}
+	b.index(addresses)
+	return changes, nil
+}
+
+func (b *AddressBook) All() []OrderAddress {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	res := make([]OrderAddress, len(b.addresses))
+	copy(res, b.addresses)
+	return res
+}
+
+func (b *AddressBook) Get(addressId int64) (OrderAddress, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	i, ok := b.byId[addressId]
+	if !ok {
+		return OrderAddress{}, false
+	}
+	return b.addresses[i], true
+}
+
+// Matching is case insensitive and ignores surrounding whitespace.
+func (b *AddressBook) FindByName(name string) []OrderAddress {
+	return b.find(b.byName, name)
+}
+
+func (b *AddressBook) FindByCompany(company string) []OrderAddress {
+	return b.find(b.byCompany, company)
+}
+
+func (b *AddressBook) FindByPostalCode(postalCode string) []OrderAddress {
+	return b.find(b.byPostalCode, postalCode)
+}
+
+// Resolve finds the single address whose contact name or company matches label.
+func (b *AddressBook) Resolve(label string) (OrderAddress, error) {
+	res := b.FindByName(label)
+	if len(res) == 0 {
+		res = b.FindByCompany(label)
+	}
+	switch len(res) {
+	case 0:
+		return OrderAddress{}, fmt.Errorf("no address found for %q", label)
+	case 1:
+		return res[0], nil
+	}
+	ids := make([]string, len(res))
+	for i, a := range res {
+		ids[i] = fmt.Sprintf("%d", a.AddressId)
+	}
+	return OrderAddress{}, fmt.Errorf("multiple addresses found for %q: %s", label, strings.Join(ids, ", "))
+}
+
+func (b *AddressBook) ResolveSender(label string) (Sender, error) {
+	a, err := b.Resolve(label)
+	if err != nil {
+		return Sender{}, err
+	}
+	return Sender{AddressId: a.AddressId}, nil
+}
+
+func (b *AddressBook) ResolveRecipient(label string) (Recipient, error) {
+	a, err := b.Resolve(label)
+	if err != nil {
+		return Recipient{}, err
+	}
+	return Recipient{AddressId: a.AddressId}, nil
+}
+
+func (b *AddressBook) find(index map[string][]int, key string) []OrderAddress {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var res []OrderAddress
+	for _, i := range index[normalizeAddressKey(key)] {
+		res = append(res, b.addresses[i])
+	}
+	return res
+}
+
+// Must be called with the lock held.
+func (b *AddressBook) index(addresses []OrderAddress) {
+	b.addresses = make([]OrderAddress, len(addresses))
+	copy(b.addresses, addresses)
+	sort.SliceStable(b.addresses, func(i, j int) bool {
+		return b.addresses[i].AddressId < b.addresses[j].AddressId
+	})
+	b.byId = make(map[int64]int, len(addresses))
+	b.byName = make(map[string][]int)
+	b.byCompany = make(map[string][]int)
+	b.byPostalCode = make(map[string][]int)
+	add := func(index map[string][]int, key string, i int) {
+		key = normalizeAddressKey(key)
+		if key != "" {
+			index[key] = append(index[key], i)
+		}
+	}
+	for i, a := range b.addresses {
+		b.byId[a.AddressId] = i
+		add(b.byName, a.Contact.Name, i)
+		add(b.byCompany, a.Contact.Company, i)
+		add(b.byPostalCode, a.Address.PostalCode, i)
+	}
+}
+
+func normalizeAddressKey(key string) string {
+	return strings.ToLower(strings.TrimSpace(key))
+}
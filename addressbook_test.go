@@ -0,0 +1,98 @@
+package coleteonline_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/radulucut/coleteonline"
+	"github.com/radulucut/coleteonline/coleteonlinetest"
+)
+
+func Test_AddressBook(t *testing.T) {
+	server := coleteonlinetest.NewServer()
+	defer server.Close()
+	addresses := []coleteonline.OrderAddress{
+		{
+			AddressId: 1,
+			Contact:   coleteonline.Contact{Name: "Main Warehouse", Company: "ACME"},
+			Address:   coleteonline.Address{PostalCode: "400001"},
+		},
+		{
+			AddressId: 2,
+			Contact:   coleteonline.Contact{Name: "Store", Company: "ACME"},
+			Address:   coleteonline.Address{PostalCode: "400001"},
+		},
+		{
+			AddressId: 3,
+			Contact:   coleteonline.Contact{Name: "Office", Company: "Other"},
+			Address:   coleteonline.Address{PostalCode: "010101"},
+		},
+	}
+	server.SetAddresses(addresses, 2)
+	client := coleteonline.NewClient(server.Config())
+	store := &coleteonline.JSONFileAddressStore{Path: filepath.Join(t.TempDir(), "addresses.json")}
+	ctx := context.Background()
+
+	book := coleteonline.NewAddressBook(client, store)
+	err := book.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := book.Resync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(changes.Added, addresses); diff != "" {
+		t.Errorf("Added mismatch (-want +got):\n%s", diff)
+	}
+
+	sender, err := book.ResolveSender(" main warehouse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(sender, coleteonline.Sender{AddressId: 1}); diff != "" {
+		t.Errorf("Sender mismatch (-want +got):\n%s", diff)
+	}
+	_, err = book.Resolve("ACME")
+	if diff := cmp.Diff(err.Error(), `multiple addresses found for "ACME": 1, 2`); diff != "" {
+		t.Errorf("Error mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(book.FindByPostalCode("400001"), addresses[:2]); diff != "" {
+		t.Errorf("Postal code mismatch (-want +got):\n%s", diff)
+	}
+
+	updated := addresses[2]
+	updated.Contact.Name = "Head Office"
+	_, err = client.UpdateAddress(3, &updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.DeleteAddress(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err = book.Resync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(*changes, coleteonline.AddressBookChanges{
+		Updated: []coleteonline.OrderAddress{updated},
+		Removed: []coleteonline.OrderAddress{addresses[1]},
+	}); diff != "" {
+		t.Errorf("Changes mismatch (-want +got):\n%s", diff)
+	}
+
+	reloaded := coleteonline.NewAddressBook(client, store)
+	err = reloaded.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(reloaded.All(), []coleteonline.OrderAddress{addresses[0], updated}); diff != "" {
+		t.Errorf("Addresses mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(reloaded.FindByName("head office"), []coleteonline.OrderAddress{updated}); diff != "" {
+		t.Errorf("Name mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,66 @@
+package coleteonline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// Returned by CreateOrder when the balance guard is enabled and the order would exceed
+// the balance. It matches ErrInsufficientBalance with errors.Is.
+type InsufficientBalanceError struct {
+	// The quoted total of the order.
+	Total Money
+	// Total plus the guard margin.
+	Required Money
+	// Balance amount plus bonus.
+	Available Money
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("%s: required %s, available %s", ErrInsufficientBalance, e.Required, e.Available)
+}
+
+func (e *InsufficientBalanceError) Is(target error) bool {
+	return target == ErrInsufficientBalance
+}
+
+type BalanceGuard struct {
+	// Added to the quoted total before comparing it with the balance.
+	Margin Money
+}
+
+// Prices the order and compares the total plus margin against the balance amount plus bonus.
+func (c *Client) checkBalance(ctx context.Context, order *Order) error {
+	price, err := c.OrderPriceWithContext(ctx, order)
+	if err != nil {
+		return err
+	}
+	balance, err := c.UserBalanceWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	available, err := balance.Amount.Add(balance.Bonus)
+	if err != nil {
+		return err
+	}
+	total := price.Selected.Price.Total
+	required, err := total.Add(c.balanceGuard.Margin)
+	if err != nil {
+		return err
+	}
+	cmp, err := required.Cmp(available)
+	if err != nil {
+		return err
+	}
+	if cmp > 0 {
+		return &InsufficientBalanceError{
+			Total:     total,
+			Required:  required,
+			Available: available,
+		}
+	}
+	return nil
+}
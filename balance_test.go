@@ -0,0 +1,55 @@
+package coleteonline_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/radulucut/coleteonline"
+	"github.com/radulucut/coleteonline/coleteonlinetest"
+)
+
+func Test_BalanceGuard(t *testing.T) {
+	server := coleteonlinetest.NewServer()
+	defer server.Close()
+	server.SetBalance(coleteonline.UserBalance{
+		Amount: coleteonline.NewMoney(2000, "RON"),
+		Bonus:  coleteonline.NewMoney(500, "RON"),
+	})
+	config := server.Config()
+	config.BalanceGuard = &coleteonline.BalanceGuard{
+		Margin: coleteonline.NewMoney(1000, "RON"),
+	}
+	client := coleteonline.NewClient(config)
+
+	order := newTestOrder()
+	_, err := client.CreateOrder(&order)
+	if !errors.Is(err, coleteonline.ErrInsufficientBalance) {
+		t.Fatalf("Expected ErrInsufficientBalance, got %v", err)
+	}
+	var bErr *coleteonline.InsufficientBalanceError
+	if !errors.As(err, &bErr) {
+		t.Fatalf("Expected *InsufficientBalanceError, got %T", err)
+	}
+	expected := coleteonline.InsufficientBalanceError{
+		Total:     coleteonline.NewMoney(2000, "RON"),
+		Required:  coleteonline.NewMoney(3000, "RON"),
+		Available: coleteonline.NewMoney(2500, "RON"),
+	}
+	if diff := cmp.Diff(*bErr, expected); diff != "" {
+		t.Errorf("Error mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(len(server.Orders()), 0); diff != "" {
+		t.Errorf("Orders mismatch (-want +got):\n%s", diff)
+	}
+
+	config.BalanceGuard.Margin = coleteonline.NewMoney(500, "RON")
+	client = coleteonline.NewClient(config)
+	_, err = client.CreateOrder(&order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(len(server.Orders()), 1); diff != "" {
+		t.Errorf("Orders mismatch (-want +got):\n%s", diff)
+	}
+}
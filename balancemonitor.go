@@ -0,0 +1,199 @@
+package coleteonline
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type BalanceAlertReason string
+
+const (
+	BalanceAlertLow      BalanceAlertReason = "low"
+	BalanceAlertFastDrop BalanceAlertReason = "fastDrop"
+)
+
+type BalanceAlert struct {
+	Reason  BalanceAlertReason
+	Balance UserBalance
+	// Amount lost per hour over the monitor window, negative when the balance grows.
+	DropPerHour Money
+	Time        time.Time
+}
+
+type BalanceSample struct {
+	Time    time.Time
+	Balance UserBalance
+}
+
+type BalanceMonitorConfig struct {
+	// Defaults to 15 minutes.
+	Interval time.Duration
+	// Alert when the amount, without bonus, falls under it. Zero disables the alert.
+	Threshold Money
+	// Alert when the amount drops faster than this per hour. Zero disables the alert.
+	MaxDropPerHour Money
+	// The period over which the drop rate is measured, defaults to 1 hour.
+	Window time.Duration
+	// Alerts fire once when their condition starts to hold and again only after it cleared.
+	OnAlert func(alert BalanceAlert)
+	// Called when the balance could not be fetched.
+	OnError func(err error)
+}
+
+// BalanceMonitor polls UserBalance and alerts when the balance is low or dropping fast.
+type BalanceMonitor struct {
+	client  *Client
+	config  BalanceMonitorConfig
+	mu      sync.Mutex
+	samples []BalanceSample
+	active  map[BalanceAlertReason]bool
+}
+
+func NewBalanceMonitor(client *Client, config BalanceMonitorConfig) *BalanceMonitor {
+	if config.Interval <= 0 {
+		config.Interval = 15 * time.Minute
+	}
+	if config.Window <= 0 {
+		config.Window = time.Hour
+	}
+	return &BalanceMonitor{
+		client: client,
+		config: config,
+		active: make(map[BalanceAlertReason]bool),
+	}
+}
+
+// Run checks the balance immediately and then at every interval until ctx is done.
+func (m *BalanceMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	for {
+		_, err := m.Check(ctx)
+		if err != nil && ctx.Err() == nil && m.config.OnError != nil {
+			m.config.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fetches the balance once, records it and fires any alerts. It returns ErrCurrencyMismatch
+// when the balance is not in the currency of the previous samples or of the configured limits.
+func (m *BalanceMonitor) Check(ctx context.Context) (*UserBalance, error) {
+	balance, err := m.client.UserBalanceWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := m.client.timeNow()
+	m.mu.Lock()
+	m.samples = append(m.samples, BalanceSample{Time: now, Balance: *balance})
+	// Keep the newest sample outside the window so the rate covers all of it
+	cutoff := now.Add(-m.config.Window)
+	i := 0
+	for i+1 < len(m.samples) && !m.samples[i+1].Time.After(cutoff) {
+		i++
+	}
+	m.samples = m.samples[i:]
+	drop, low, fast, err := m.conditions(balance.Amount)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	var alerts []BalanceAlert
+	for _, c := range []struct {
+		reason BalanceAlertReason
+		holds  bool
+	}{
+		{BalanceAlertLow, low},
+		{BalanceAlertFastDrop, fast},
+	} {
+		if c.holds && !m.active[c.reason] {
+			alerts = append(alerts, BalanceAlert{
+				Reason:      c.reason,
+				Balance:     *balance,
+				DropPerHour: drop,
+				Time:        now,
+			})
+		}
+		m.active[c.reason] = c.holds
+	}
+	m.mu.Unlock()
+	if m.config.OnAlert != nil {
+		for _, alert := range alerts {
+			m.config.OnAlert(alert)
+		}
+	}
+	return balance, nil
+}
+
+// The samples within the window, oldest first.
+func (m *BalanceMonitor) Trend() []BalanceSample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res := make([]BalanceSample, len(m.samples))
+	copy(res, m.samples)
+	return res
+}
+
+// DropPerHour returns the amount lost per hour over the window, false when there are not enough samples.
+func (m *BalanceMonitor) DropPerHour() (Money, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.dropPerHour()
+}
+
+// Must be called with the lock held.
+func (m *BalanceMonitor) dropPerHour() (Money, bool, error) {
+	if len(m.samples) < 2 {
+		return Money{}, false, nil
+	}
+	first := m.samples[0]
+	last := m.samples[len(m.samples)-1]
+	elapsed := last.Time.Sub(first.Time)
+	if elapsed <= 0 {
+		return Money{}, false, nil
+	}
+	drop, err := first.Balance.Amount.Sub(last.Balance.Amount)
+	if err != nil {
+		return Money{}, false, err
+	}
+	perHour := math.Round(float64(drop.Amount) * float64(time.Hour) / float64(elapsed))
+	return Money{Amount: int64(perHour), Currency: drop.Currency}, true, nil
+}
+
+// Returns the drop rate and whether the low and fast drop alerts hold for the amount.
+// Must be called with the lock held.
+func (m *BalanceMonitor) conditions(amount Money) (drop Money, low bool, fast bool, err error) {
+	drop, hasDrop, err := m.dropPerHour()
+	if err != nil {
+		return Money{}, false, false, err
+	}
+	low, err = crosses(amount, m.config.Threshold, -1)
+	if err != nil {
+		return Money{}, false, false, err
+	}
+	if hasDrop {
+		fast, err = crosses(drop, m.config.MaxDropPerHour, 1)
+		if err != nil {
+			return Money{}, false, false, err
+		}
+	}
+	return drop, low, fast, nil
+}
+
+// Reports whether amount compares to a non-zero limit as sign, e.g. -1 for under the limit.
+func crosses(amount Money, limit Money, sign int) (bool, error) {
+	if limit.IsZero() {
+		return false, nil
+	}
+	c, err := amount.Cmp(limit)
+	if err != nil {
+		return false, err
+	}
+	return c == sign, nil
+}
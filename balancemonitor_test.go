@@ -0,0 +1,108 @@
+package coleteonline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_BalanceMonitor(t *testing.T) {
+	var balance UserBalance
+	client := NewClient(Config{
+		ClientId:     "client_id",
+		ClientSecret: "client_secret",
+		APIURL:       "http://localhost/v1",
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			b, _ := json.Marshal(balance)
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	})
+	now := currentTime
+	client.timeNow = func() time.Time {
+		return now
+	}
+	client.authBearer = "Bearer token"
+	client.authBearerExp = now.Add(24 * time.Hour)
+	var alerts []BalanceAlert
+	monitor := NewBalanceMonitor(client, BalanceMonitorConfig{
+		Threshold:      NewMoney(10000, "RON"),
+		MaxDropPerHour: NewMoney(10000, "RON"),
+		Window:         time.Hour,
+		OnAlert: func(alert BalanceAlert) {
+			alerts = append(alerts, alert)
+		},
+	})
+	ctx := context.Background()
+	check := func(amount int64) {
+		balance = UserBalance{Amount: NewMoney(amount, "RON"), Bonus: NewMoney(0, "RON")}
+		_, err := monitor.Check(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(30 * time.Minute)
+	}
+
+	check(50000)
+	check(48000)
+	check(45000)
+	if diff := cmp.Diff(len(alerts), 0); diff != "" {
+		t.Errorf("Alerts mismatch (-want +got):\n%s", diff)
+	}
+	check(40000)
+	check(30000)
+	check(25000)
+	expected := []BalanceAlert{
+		{
+			Reason:      BalanceAlertFastDrop,
+			Balance:     UserBalance{Amount: NewMoney(30000, "RON"), Bonus: NewMoney(0, "RON")},
+			DropPerHour: NewMoney(15000, "RON"),
+			Time:        currentTime.Add(2 * time.Hour),
+		},
+	}
+	if diff := cmp.Diff(alerts, expected); diff != "" {
+		t.Errorf("Alerts mismatch (-want +got):\n%s", diff)
+	}
+
+	alerts = nil
+	check(25000)
+	check(9000)
+	check(8000)
+	expected = []BalanceAlert{
+		{
+			Reason:      BalanceAlertLow,
+			Balance:     UserBalance{Amount: NewMoney(9000, "RON"), Bonus: NewMoney(0, "RON")},
+			DropPerHour: NewMoney(16000, "RON"),
+			Time:        currentTime.Add(3*time.Hour + 30*time.Minute),
+		},
+		{
+			Reason:      BalanceAlertFastDrop,
+			Balance:     UserBalance{Amount: NewMoney(9000, "RON"), Bonus: NewMoney(0, "RON")},
+			DropPerHour: NewMoney(16000, "RON"),
+			Time:        currentTime.Add(3*time.Hour + 30*time.Minute),
+		},
+	}
+	if diff := cmp.Diff(alerts, expected); diff != "" {
+		t.Errorf("Alerts mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(len(monitor.Trend()), 3); diff != "" {
+		t.Errorf("Trend mismatch (-want +got):\n%s", diff)
+	}
+
+	monitor = NewBalanceMonitor(client, BalanceMonitorConfig{
+		Threshold: NewMoney(10000, "EUR"),
+	})
+	_, err := monitor.Check(ctx)
+	if !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package coleteonline
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Set on the results of orders that were not sent because an earlier order failed.
+var ErrOrderSkipped = errors.New("order skipped after an earlier error")
+
+type CreateOrdersOptions struct {
+	// Number of orders created concurrently, defaults to 4.
+	Concurrency int
+	// Maximum number of orders sent per second, zero means no limit.
+	RateLimit float64
+	// Stops sending orders after the first failure. Orders already in flight still complete.
+	StopOnError bool
+}
+
+type CreateOrderResult struct {
+	Response *OrderResponse
+	// A *ResponseError when the API rejected the order, ErrOrderSkipped when it was not sent.
+	Err error
+}
+
+// CreateOrders creates the orders concurrently and returns one result per order, in input order.
+// Orders not sent before ctx is done get the context error.
+func (c *Client) CreateOrders(ctx context.Context, orders []Order, options CreateOrdersOptions) []CreateOrderResult {
+	if options.Concurrency <= 0 {
+		options.Concurrency = 4
+	}
+	results := make([]CreateOrderResult, len(orders))
+	limiter := &rateLimiter{client: c}
+	if options.RateLimit > 0 {
+		limiter.interval = time.Duration(float64(time.Second) / options.RateLimit)
+	}
+	var mu sync.Mutex
+	failed := false
+	next := 0
+	// Each worker takes the next order in input order, so no order is sent after a skipped one
+	take := func() (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if next >= len(orders) {
+			return -1, nil
+		}
+		i := next
+		next++
+		if failed {
+			return i, ErrOrderSkipped
+		}
+		if ctx.Err() != nil {
+			return i, ctx.Err()
+		}
+		return i, nil
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < options.Concurrency && w < len(orders); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i, err := take()
+				if i < 0 {
+					return
+				}
+				if err == nil {
+					err = limiter.wait(ctx)
+				}
+				if err == nil {
+					results[i].Response, err = c.CreateOrderWithContext(ctx, &orders[i])
+				}
+				results[i].Err = err
+				if err != nil && options.StopOnError {
+					mu.Lock()
+					failed = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Spaces requests at least interval apart, measured with the client clock.
+type rateLimiter struct {
+	client   *Client
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.interval <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := l.client.timeNow()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+	if d := at.Sub(now); d > 0 {
+		return l.client.sleep(ctx, d)
+	}
+	return nil
+}
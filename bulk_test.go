@@ -0,0 +1,57 @@
+package coleteonline_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/radulucut/coleteonline"
+	"github.com/radulucut/coleteonline/coleteonlinetest"
+)
+
+func Test_CreateOrders(t *testing.T) {
+	server := coleteonlinetest.NewServer()
+	defer server.Close()
+	client := coleteonline.NewClient(server.Config())
+	ctx := context.Background()
+	invalid := newTestOrder()
+	invalid.Packages.List = nil
+	orders := []coleteonline.Order{newTestOrder(), invalid, newTestOrder(), newTestOrder()}
+
+	results := client.CreateOrders(ctx, orders, coleteonline.CreateOrdersOptions{Concurrency: 3})
+	if diff := cmp.Diff(len(results), 4); diff != "" {
+		t.Fatalf("Results mismatch (-want +got):\n%s", diff)
+	}
+	var rErr *coleteonline.ResponseError
+	if !errors.As(results[1].Err, &rErr) || rErr.Code != 400 {
+		t.Errorf("Expected a validation *ResponseError, got %v", results[1].Err)
+	}
+	for _, i := range []int{0, 2, 3} {
+		if results[i].Err != nil || results[i].Response == nil {
+			t.Errorf("Result %d: expected a response, got %v", i, results[i].Err)
+		}
+	}
+	if diff := cmp.Diff(len(server.Orders()), 3); diff != "" {
+		t.Errorf("Orders mismatch (-want +got):\n%s", diff)
+	}
+
+	results = client.CreateOrders(ctx, orders, coleteonline.CreateOrdersOptions{
+		Concurrency: 1,
+		StopOnError: true,
+	})
+	if results[0].Err != nil {
+		t.Errorf("Result 0: expected a response, got %v", results[0].Err)
+	}
+	if !errors.As(results[1].Err, &rErr) {
+		t.Errorf("Result 1: expected *ResponseError, got %v", results[1].Err)
+	}
+	for _, i := range []int{2, 3} {
+		if !errors.Is(results[i].Err, coleteonline.ErrOrderSkipped) {
+			t.Errorf("Result %d: expected ErrOrderSkipped, got %v", i, results[i].Err)
+		}
+	}
+	if diff := cmp.Diff(len(server.Orders()), 4); diff != "" {
+		t.Errorf("Orders mismatch (-want +got):\n%s", diff)
+	}
+}
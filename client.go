@@ -2,12 +2,16 @@ package coleteonline
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -21,8 +25,14 @@ type Client struct {
 	authBasic     string
 	authBearer    string
 	authBearerExp time.Time
+	userAgent     string
+	lang          string
 	http          *http.Client
+	retry         RetryPolicy
+	balanceGuard  *BalanceGuard
 	timeNow       func() time.Time
+	sleep         func(ctx context.Context, d time.Duration) error
+	random        func() float64
 }
 
 type Config struct {
@@ -30,6 +40,20 @@ type Config struct {
 	ClientSecret  string
 	UseProduction bool
 	Timeout       time.Duration
+	// Defaults to DefaultRetryPolicy when nil. Unset backoff durations are taken from it too.
+	Retry *RetryPolicy
+	// Override the default endpoints, e.g. to point at a proxy or a local stub.
+	AuthURL string
+	APIURL  string
+	// Used as-is when set, in which case Timeout and Transport are ignored.
+	HTTPClient *http.Client
+	Transport  http.RoundTripper
+	UserAgent  string
+	// Preferred language of status texts, sent as Accept-Language.
+	Language string
+	// When set, CreateOrder prices the order and checks the balance before submitting it,
+	// returning an *InsufficientBalanceError instead of creating the order.
+	BalanceGuard *BalanceGuard
 }
 
 func NewClient(config Config) *Client {
@@ -38,26 +62,56 @@ func NewClient(config Config) *Client {
 		authBasic: "Basic " + base64.StdEncoding.EncodeToString(
 			[]byte(config.ClientId+":"+config.ClientSecret),
 		),
+		userAgent:    config.UserAgent,
+		lang:         config.Language,
+		balanceGuard: config.BalanceGuard,
 		http: &http.Client{
-			Timeout: config.Timeout,
+			Timeout:   config.Timeout,
+			Transport: config.Transport,
 		},
+		retry: DefaultRetryPolicy,
 		timeNow: func() time.Time {
 			return time.Now()
 		},
+		sleep:  sleep,
+		random: rand.Float64,
 	}
 	if config.UseProduction {
 		client.apiURL = "https://api.colete-online.ro/v1"
 	} else {
 		client.apiURL = "https://api.colete-online.ro/v1/staging"
 	}
+	if config.AuthURL != "" {
+		client.authURL = config.AuthURL
+	}
+	if config.APIURL != "" {
+		client.apiURL = strings.TrimSuffix(config.APIURL, "/")
+	}
+	if config.HTTPClient != nil {
+		client.http = config.HTTPClient
+	}
+	if config.Retry != nil {
+		client.retry = *config.Retry
+		if client.retry.MinBackoff <= 0 {
+			client.retry.MinBackoff = DefaultRetryPolicy.MinBackoff
+		}
+		if client.retry.MaxBackoff <= 0 {
+			client.retry.MaxBackoff = DefaultRetryPolicy.MaxBackoff
+		}
+	}
 	return client
 }
 
 func (c *Client) GetAuthBearer() (*string, error) {
+	return c.GetAuthBearerWithContext(context.Background())
+}
+
+func (c *Client) GetAuthBearerWithContext(ctx context.Context) (*string, error) {
 	c.Lock()
 	defer c.Unlock()
 	if c.timeNow().After(c.authBearerExp) {
-		req, err := http.NewRequest(
+		req, err := http.NewRequestWithContext(
+			ctx,
 			"POST",
 			c.authURL,
 			strings.NewReader("grant_type=client_credentials"),
@@ -67,6 +121,9 @@ func (c *Client) GetAuthBearer() (*string, error) {
 		}
 		req.Header.Set("Authorization", c.authBasic)
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if c.userAgent != "" {
+			req.Header.Set("User-Agent", c.userAgent)
+		}
 		r, err := c.http.Do(req)
 		if err != nil {
 			return nil, err
@@ -76,12 +133,19 @@ func (c *Client) GetAuthBearer() (*string, error) {
 		if err != nil {
 			return nil, err
 		}
-		if r.StatusCode != 200 {
+		if !isSuccessStatus(r.StatusCode) {
 			var rErr AuthResponseError
 			err = json.Unmarshal(b, &rErr)
 			if err != nil {
-				return nil, err
+				rErr = AuthResponseError{
+					Name:        "unexpected_response_status",
+					Description: http.StatusText(r.StatusCode),
+					Err:         err,
+				}
 			}
+			rErr.StatusCode = r.StatusCode
+			rErr.RequestId = r.Header.Get("X-Request-Id")
+			rErr.Body = b
 			return nil, &rErr
 		}
 		var res AuthToken
@@ -99,8 +163,26 @@ func (c *Client) GetAuthBearer() (*string, error) {
 }
 
 func (c *Client) CreateOrder(order *Order) (*OrderResponse, error) {
+	return c.CreateOrderWithContext(context.Background(), order)
+}
+
+func (c *Client) CreateOrderWithContext(ctx context.Context, order *Order) (*OrderResponse, error) {
+	return c.createOrder(ctx, order, nil)
+}
+
+// Calls sent, when not nil, right before the order request is handed to the transport.
+func (c *Client) createOrder(ctx context.Context, order *Order, sent func()) (*OrderResponse, error) {
+	if c.balanceGuard != nil {
+		err := c.checkBalance(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sent != nil {
+		ctx = context.WithValue(ctx, requestSentKey{}, sent)
+	}
 	var res OrderResponse
-	err := c.request("POST", "/order", order, &res)
+	err := c.request(ctx, "POST", "/order", order, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -108,8 +190,12 @@ func (c *Client) CreateOrder(order *Order) (*OrderResponse, error) {
 }
 
 func (c *Client) OrderPrice(order *Order) (*OrderPriceResponse, error) {
+	return c.OrderPriceWithContext(context.Background(), order)
+}
+
+func (c *Client) OrderPriceWithContext(ctx context.Context, order *Order) (*OrderPriceResponse, error) {
 	var res OrderPriceResponse
-	err := c.request("POST", "/order/price", order, &res)
+	err := c.request(ctx, "POST", "/order/price", order, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -117,26 +203,128 @@ func (c *Client) OrderPrice(order *Order) (*OrderPriceResponse, error) {
 }
 
 func (c *Client) OrderStatus(uniqueIdOrAWB *string) (*OrderStatusResponse, error) {
+	return c.OrderStatusWithContext(context.Background(), uniqueIdOrAWB)
+}
+
+func (c *Client) OrderStatusWithContext(ctx context.Context, uniqueIdOrAWB *string) (*OrderStatusResponse, error) {
 	var res OrderStatusResponse
-	err := c.request("GET", "/order/status/"+*uniqueIdOrAWB, nil, &res)
+	err := c.request(ctx, "GET", "/order/status/"+url.PathEscape(*uniqueIdOrAWB), nil, &res)
 	if err != nil {
 		return nil, err
 	}
 	return &res, nil
 }
 
+func (c *Client) OrderAWB(uniqueId *string, format AWBFormat) (*OrderAWBResponse, error) {
+	return c.OrderAWBWithContext(context.Background(), uniqueId, format)
+}
+
+// The caller is responsible for closing the returned Body.
+func (c *Client) OrderAWBWithContext(ctx context.Context, uniqueId *string, format AWBFormat) (*OrderAWBResponse, error) {
+	path := "/order/awb/" + url.PathEscape(*uniqueId)
+	if format != "" {
+		path += "?formatType=" + url.QueryEscape(string(format))
+	}
+	r, err := c.stream(ctx, "GET", path)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderAWBResponse{
+		Body:          r.Body,
+		ContentType:   r.Header.Get("Content-Type"),
+		ContentLength: r.ContentLength,
+	}, nil
+}
+
+func (c *Client) OrderAWBToFile(uniqueId *string, format AWBFormat, name string) error {
+	return c.OrderAWBToFileWithContext(context.Background(), uniqueId, format, name)
+}
+
+func (c *Client) OrderAWBToFileWithContext(ctx context.Context, uniqueId *string, format AWBFormat, name string) error {
+	res, err := c.OrderAWBWithContext(ctx, uniqueId, format)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, res.Body)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 func (c *Client) AddressList(page int64) (*AddressListResponse, error) {
+	return c.AddressListWithContext(context.Background(), page)
+}
+
+func (c *Client) AddressListWithContext(ctx context.Context, page int64) (*AddressListResponse, error) {
 	var res AddressListResponse
-	err := c.request("GET", fmt.Sprintf("/address?page=%d", page), nil, &res)
+	err := c.request(ctx, "GET", fmt.Sprintf("/address?page=%d", page), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) GetAddress(addressId int64) (*OrderAddress, error) {
+	return c.GetAddressWithContext(context.Background(), addressId)
+}
+
+func (c *Client) GetAddressWithContext(ctx context.Context, addressId int64) (*OrderAddress, error) {
+	var res OrderAddress
+	err := c.request(ctx, "GET", fmt.Sprintf("/address/%d", addressId), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) CreateAddress(address *OrderAddress) (*OrderAddress, error) {
+	return c.CreateAddressWithContext(context.Background(), address)
+}
+
+func (c *Client) CreateAddressWithContext(ctx context.Context, address *OrderAddress) (*OrderAddress, error) {
+	var res OrderAddress
+	err := c.request(ctx, "POST", "/address", address, &res)
 	if err != nil {
 		return nil, err
 	}
 	return &res, nil
 }
 
+func (c *Client) UpdateAddress(addressId int64, address *OrderAddress) (*OrderAddress, error) {
+	return c.UpdateAddressWithContext(context.Background(), addressId, address)
+}
+
+func (c *Client) UpdateAddressWithContext(ctx context.Context, addressId int64, address *OrderAddress) (*OrderAddress, error) {
+	var res OrderAddress
+	err := c.request(ctx, "PUT", fmt.Sprintf("/address/%d", addressId), address, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) DeleteAddress(addressId int64) error {
+	return c.DeleteAddressWithContext(context.Background(), addressId)
+}
+
+func (c *Client) DeleteAddressWithContext(ctx context.Context, addressId int64) error {
+	return c.request(ctx, "DELETE", fmt.Sprintf("/address/%d", addressId), nil, nil)
+}
+
 func (c *Client) ServiceList() ([]ServiceResponse, error) {
+	return c.ServiceListWithContext(context.Background())
+}
+
+func (c *Client) ServiceListWithContext(ctx context.Context) ([]ServiceResponse, error) {
 	var res []ServiceResponse
-	err := c.request("GET", "/service", nil, &res)
+	err := c.request(ctx, "GET", "/service", nil, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -144,8 +332,12 @@ func (c *Client) ServiceList() ([]ServiceResponse, error) {
 }
 
 func (c *Client) UserBalance() (*UserBalance, error) {
+	return c.UserBalanceWithContext(context.Background())
+}
+
+func (c *Client) UserBalanceWithContext(ctx context.Context) (*UserBalance, error) {
 	var res UserBalance
-	err := c.request("GET", "/user/balance", nil, &res)
+	err := c.request(ctx, "GET", "/user/balance", nil, &res)
 	if err != nil {
 		return nil, err
 	}
@@ -153,65 +345,187 @@ func (c *Client) UserBalance() (*UserBalance, error) {
 }
 
 func (c *Client) request(
+	ctx context.Context,
 	method string,
 	path string,
 	body interface{},
 	res interface{},
 ) error {
-	token, err := c.GetAuthBearer()
+	r, err := c.do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(r.Body, 1<<20)) // 1MB
 	if err != nil {
 		return err
 	}
+	if !isSuccessStatus(r.StatusCode) {
+		return newResponseError(r, b)
+	}
+	// 204 and other responses without a body leave res unchanged
+	if res == nil || len(bytes.TrimSpace(b)) == 0 {
+		return nil
+	}
+	err = json.Unmarshal(b, res)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// The caller is responsible for closing the body of the returned response.
+func (c *Client) stream(
+	ctx context.Context,
+	method string,
+	path string,
+) (*http.Response, error) {
+	r, err := c.do(ctx, method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if isSuccessStatus(r.StatusCode) {
+		return r, nil
+	}
+	defer r.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(r.Body, 1<<20)) // 1MB
+	if err != nil {
+		return nil, err
+	}
+	return nil, newResponseError(r, b)
+}
+
+// Decodes the error body when there is one, keeping the status, request id and raw body.
+func newResponseError(r *http.Response, b []byte) *ResponseError {
+	res := &ResponseError{}
+	if len(bytes.TrimSpace(b)) != 0 {
+		err := json.Unmarshal(b, res)
+		if err != nil {
+			res = &ResponseError{Err: err}
+		}
+	}
+	if res.Message == "" {
+		res.Message = "unexpected response status"
+		if res.Code == 0 {
+			res.Code = r.StatusCode
+		}
+	}
+	res.StatusCode = r.StatusCode
+	res.RequestId = r.Header.Get("X-Request-Id")
+	res.Body = b
+	return res
+}
+
+type requestSentKey struct{}
+
+func isSuccessStatus(code int) bool {
+	return code >= 200 && code < 300
+}
+
+// Sends the request, refreshing the token once on 401 and retrying according to the retry policy.
+// 429 responses are always retried since the request was not processed, while network errors
+// and 5xx responses are only retried for idempotent methods.
+// The caller is responsible for closing the body of the returned response.
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	path string,
+	body interface{},
+) (*http.Response, error) {
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		req, err := c.newRequest(ctx, method, path, body)
+		if err != nil {
+			return nil, err
+		}
+		if sent, ok := ctx.Value(requestSentKey{}).(func()); ok {
+			sent()
+		}
+		r, err := c.http.Do(req)
+		if err != nil {
+			if ctx.Err() != nil || !isIdempotent(method) || attempt >= c.retry.MaxAttempts {
+				return nil, err
+			}
+			err = c.sleep(ctx, c.retry.backoff(attempt, c.random()))
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// Unauthorized: the cached token may have expired or been revoked
+		if r.StatusCode == 401 && !refreshed {
+			r.Body.Close()
+			c.invalidateAuthBearer(req.Header.Get("Authorization"))
+			refreshed = true
+			attempt--
+			continue
+		}
+		retryable := r.StatusCode == 429 ||
+			(isRetryableStatus(r.StatusCode) && isIdempotent(method))
+		if !retryable || attempt >= c.retry.MaxAttempts {
+			return r, nil
+		}
+		wait := c.retry.backoff(attempt, c.random())
+		// Retry-After is capped so a misbehaving server cannot block the caller for hours
+		if d, ok := parseRetryAfter(r.Header.Get("Retry-After"), c.timeNow()); ok {
+			wait = d
+			if wait > c.retry.MaxBackoff {
+				wait = c.retry.MaxBackoff
+			}
+		}
+		r.Body.Close()
+		err = c.sleep(ctx, wait)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) newRequest(
+	ctx context.Context,
+	method string,
+	path string,
+	body interface{},
+) (*http.Request, error) {
+	token, err := c.GetAuthBearerWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var b []byte
 	if body != nil {
 		b, err = json.Marshal(body)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		method,
 		c.apiURL+path,
 		bytes.NewReader(b),
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	req.Header.Set("Authorization", *token)
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	r, err := c.http.Do(req)
-	if err != nil {
-		return err
-	}
-	defer r.Body.Close()
-	if r.StatusCode == 200 || r.StatusCode == 400 {
-		b, err = io.ReadAll(io.LimitReader(r.Body, 1<<20)) // 1MB
-		if err != nil {
-			return err
-		}
-		if r.StatusCode == 400 {
-			var rErr ResponseError
-			err = json.Unmarshal(b, &rErr)
-			if err != nil {
-				return err
-			}
-			return &rErr
-		}
-		err = json.Unmarshal(b, res)
-		if err != nil {
-			return err
-		}
-		return nil
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
 	}
-	// Unauthorized: retry request in case the token expired
-	if r.StatusCode == 401 {
-		return c.request(method, path, body, res)
+	if lang := c.language(ctx); lang != "" {
+		req.Header.Set("Accept-Language", lang)
 	}
-	return &ResponseError{
-		Message: "unexpected response status",
-		Code:    r.StatusCode,
+	return req, nil
+}
+
+func (c *Client) invalidateAuthBearer(token string) {
+	c.Lock()
+	defer c.Unlock()
+	// Another request may have already refreshed it
+	if c.authBearer == token {
+		c.authBearerExp = time.Time{}
 	}
 }
 
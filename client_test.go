@@ -1,11 +1,18 @@
 package coleteonline
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -14,6 +21,13 @@ import (
 
 var currentTime time.Time = time.Now()
 
+var (
+	unauthorizedCalls int32
+	flakyCalls        int32
+	rateLimitedCalls  int32
+	unavailableCalls  int32
+)
+
 func Test_Client(t *testing.T) {
 	server, err := startServer()
 	if err != nil {
@@ -33,35 +47,617 @@ func Test_Client(t *testing.T) {
 		client.timeNow = func() time.Time {
 			return currentTime
 		}
-		token, err := client.GetAuthBearer()
+		token, err := client.GetAuthBearer()
+		if err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(*token, "Bearer "+getTestJWT(currentTime.Add(2*time.Hour).Unix())); diff != "" {
+			t.Errorf("Token mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Config", func(t *testing.T) {
+		t.Parallel()
+		var userAgents []string
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+			AuthURL:       url + "/auth/token",
+			APIURL:        url + "/v1/",
+			Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				userAgents = append(userAgents, r.URL.Path+" "+r.Header.Get("User-Agent"))
+				return http.DefaultTransport.RoundTrip(r)
+			}),
+			UserAgent: "test-agent",
+		})
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		res, err := client.UserBalance()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(*res, newUserBalance()); diff != "" {
+			t.Errorf("User balance mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(userAgents, []string{"/auth/token test-agent", "/v1/user/balance test-agent"}); diff != "" {
+			t.Errorf("User agents mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("GetAuthBearer_Timeout", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       100 * time.Millisecond,
+		})
+		client.authURL = url + "/auth/timeout/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		_, err := client.GetAuthBearer()
+		if diff := cmp.Diff(err.Error(), "Post \"http://"+server.Addr+"/auth/timeout/token\": context deadline exceeded (Client.Timeout exceeded while awaiting headers)"); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("CreateOrder", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		order := newOrder()
+		res, err := client.CreateOrder(&order)
+		if err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(*res, newOrderResponse()); diff != "" {
+			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("CreateOrder_Timeout", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       100 * time.Millisecond,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1/timeout"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		order := newOrder()
+		_, err := client.CreateOrder(&order)
+		if diff := cmp.Diff(err.Error(), "Post \"http://"+server.Addr+"/v1/timeout/order\": context deadline exceeded (Client.Timeout exceeded while awaiting headers)"); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("CreateOrderWithContext_Deadline", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1/timeout"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		order := newOrder()
+		_, err := client.CreateOrderWithContext(ctx, &order)
+		if diff := cmp.Diff(err.Error(), "Post \"http://"+server.Addr+"/v1/timeout/order\": context deadline exceeded"); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("CreateOrderWithContext_Canceled", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		order := newOrder()
+		_, err := client.CreateOrderWithContext(ctx, &order)
+		if diff := cmp.Diff(err.Error(), "Post \"http://"+server.Addr+"/auth/token\": context canceled"); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("CreateOrder_UseAddressIds", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1/order-with-address-id"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		order := newOrderWithAddressId()
+		res, err := client.CreateOrder(&order)
+		if err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(*res, newOrderResponse()); diff != "" {
+			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Retry_Unauthorized", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1/unauthorized"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		_, err := client.UserBalance()
+		if diff := cmp.Diff(err.Error(), `401: "unexpected response status"`); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("Expected ErrUnauthorized, got %v", err)
+		}
+		if diff := cmp.Diff(atomic.LoadInt32(&unauthorizedCalls), int32(2)); diff != "" {
+			t.Errorf("Calls mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("Retry_Flaky", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+			Retry: &RetryPolicy{
+				MaxAttempts: 5,
+				MinBackoff:  100 * time.Millisecond,
+				MaxBackoff:  time.Second,
+			},
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1/flaky"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		client.random = func() float64 {
+			return 0.5
+		}
+		var waits []time.Duration
+		client.sleep = func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		}
+		res, err := client.UserBalance()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(*res, newUserBalance()); diff != "" {
+			t.Errorf("User balance mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(waits, []time.Duration{time.Second, 150 * time.Millisecond}); diff != "" {
+			t.Errorf("Waits mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("Retry_PartialPolicy", func(t *testing.T) {
+		t.Parallel()
+		var calls int
+		client := NewClient(Config{
+			ClientId:     "client_id",
+			ClientSecret: "client_secret",
+			APIURL:       "http://localhost/v1",
+			Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				calls++
+				res := &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader("")),
+				}
+				switch calls {
+				case 1:
+					res.StatusCode = http.StatusTooManyRequests
+					res.Header.Set("Retry-After", "2")
+				case 3:
+					b, _ := json.Marshal(newUserBalance())
+					res.StatusCode = http.StatusOK
+					res.Body = io.NopCloser(bytes.NewReader(b))
+				}
+				return res, nil
+			}),
+			Retry: &RetryPolicy{MaxAttempts: 5},
+		})
+		client.authBearer = "Bearer token"
+		client.authBearerExp = time.Now().Add(time.Hour)
+		client.random = func() float64 {
+			return 0.5
+		}
+		var waits []time.Duration
+		client.sleep = func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		}
+		_, err := client.UserBalance()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(waits, []time.Duration{2 * time.Second, 750 * time.Millisecond}); diff != "" {
+			t.Errorf("Waits mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("Retry_MaxAttempts", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1/rate-limited"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		client.sleep = func(ctx context.Context, d time.Duration) error {
+			return nil
+		}
+		order := newOrder()
+		_, err := client.CreateOrder(&order)
+		if diff := cmp.Diff(err.Error(), `429: "unexpected response status"`); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+		if !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Expected ErrRateLimited, got %v", err)
+		}
+		if diff := cmp.Diff(atomic.LoadInt32(&rateLimitedCalls), int32(DefaultRetryPolicy.MaxAttempts)); diff != "" {
+			t.Errorf("Calls mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("Retry_NotIdempotent", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1/unavailable"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		client.sleep = func(ctx context.Context, d time.Duration) error {
+			return nil
+		}
+		order := newOrder()
+		_, err := client.CreateOrder(&order)
+		if diff := cmp.Diff(err.Error(), `503: "unexpected response status"`); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+		if !errors.Is(err, ErrServer) {
+			t.Errorf("Expected ErrServer, got %v", err)
+		}
+		if diff := cmp.Diff(atomic.LoadInt32(&unavailableCalls), int32(1)); diff != "" {
+			t.Errorf("Calls mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("OrderOrice", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		order := newOrder()
+		res, err := client.OrderPrice(&order)
+		if err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(*res, newOrderPriceResponse()); diff != "" {
+			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		}
+	})
+	t.Run("OrderPrice_UseAddressIds", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1/order-with-address-id"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		order := newOrderWithAddressId()
+		res, err := client.OrderPrice(&order)
+		if err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(*res, newOrderPriceResponse()); diff != "" {
+			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("OrderStatus", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		id := "id_1234"
+		res, err := client.OrderStatus(&id)
+		if err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(*res, newOrderStatusResponse()); diff != "" {
+			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("OrderStatusEscaped", func(t *testing.T) {
+		t.Parallel()
+		var path string
+		client := NewClient(Config{
+			ClientId:     "client_id",
+			ClientSecret: "client_secret",
+			APIURL:       "http://localhost/v1",
+			Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				path = r.URL.EscapedPath()
+				return nil, context.Canceled
+			}),
+			Retry: &RetryPolicy{MaxAttempts: 1},
+		})
+		client.authBearer = "Bearer token"
+		client.authBearerExp = time.Now().Add(time.Hour)
+		id := "ref/1?a"
+		client.OrderStatus(&id)
+		if diff := cmp.Diff(path, "/v1/order/status/ref%2F1%3Fa"); diff != "" {
+			t.Errorf("Path mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("OrderAWB", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		id := "id_1234"
+		res, err := client.OrderAWB(&id, AWBFormatA6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		if diff := cmp.Diff(res.ContentType, "application/pdf"); diff != "" {
+			t.Errorf("Content type mismatch (-want +got):\n%s", diff)
+		}
+		b, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if !bytes.Equal(b, newOrderAWB()) {
+			t.Errorf("AWB mismatch: got %d bytes, want %d", len(b), len(newOrderAWB()))
+		}
+	})
+	t.Run("OrderAWBToFile", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		id := "id_1234"
+		name := filepath.Join(t.TempDir(), "awb.pdf")
+		err := client.OrderAWBToFile(&id, AWBFormatA6, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(name)
+		if err != nil {
+			t.Error(err)
+		}
+		if !bytes.Equal(b, newOrderAWB()) {
+			t.Errorf("AWB mismatch: got %d bytes, want %d", len(b), len(newOrderAWB()))
+		}
+	})
+	t.Run("OrderAWB_InvalidId", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		id := "id_0000"
+		_, err := client.OrderAWB(&id, AWBFormatA6)
+		if diff := cmp.Diff(err.Error(), `400: "Invalid uniqueId"`); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("AddressList", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		res, err := client.AddressList(1)
+		if err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(*res, newAddressListResponse()); diff != "" {
+			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("GetAddress", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		res, err := client.GetAddress(1)
+		if err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(*res, newOrderAddress()); diff != "" {
+			t.Errorf("Address mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("CreateAddress", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		address := newOrderAddress()
+		address.AddressId = 0
+		res, err := client.CreateAddress(&address)
+		if err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(*res, newOrderAddress()); diff != "" {
+			t.Errorf("Address mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("UpdateAddress", func(t *testing.T) {
+		t.Parallel()
+		client := NewClient(Config{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			UseProduction: true,
+			Timeout:       10 * time.Second,
+		})
+		client.authURL = url + "/auth/token"
+		client.apiURL = url + "/v1"
+		client.timeNow = func() time.Time {
+			return currentTime
+		}
+		address := newOrderAddress()
+		address.Contact.Name = "New Name"
+		res, err := client.UpdateAddress(1, &address)
 		if err != nil {
 			t.Error(err)
 		}
-		if diff := cmp.Diff(*token, "Bearer "+getTestJWT(currentTime.Add(2*time.Hour).Unix())); diff != "" {
-			t.Errorf("Token mismatch (-want +got):\n%s", diff)
+		if diff := cmp.Diff(*res, address); diff != "" {
+			t.Errorf("Address mismatch (-want +got):\n%s", diff)
 		}
 	})
 
-	t.Run("GetAuthBearer_Timeout", func(t *testing.T) {
+	t.Run("DeleteAddress", func(t *testing.T) {
 		t.Parallel()
 		client := NewClient(Config{
 			ClientId:      "client_id",
 			ClientSecret:  "client_secret",
 			UseProduction: true,
-			Timeout:       100 * time.Millisecond,
+			Timeout:       10 * time.Second,
 		})
-		client.authURL = url + "/auth/timeout/token"
+		client.authURL = url + "/auth/token"
 		client.apiURL = url + "/v1"
 		client.timeNow = func() time.Time {
 			return currentTime
 		}
-		_, err := client.GetAuthBearer()
-		if diff := cmp.Diff(err.Error(), "Post \"http://"+server.Addr+"/auth/timeout/token\": context deadline exceeded (Client.Timeout exceeded while awaiting headers)"); diff != "" {
+		err := client.DeleteAddress(1)
+		if err != nil {
+			t.Error(err)
+		}
+		err = client.DeleteAddress(2)
+		if diff := cmp.Diff(err.Error(), `400: "Address not found"`); diff != "" {
 			t.Errorf("Error mismatch (-want +got):\n%s", diff)
 		}
 	})
 
-	t.Run("CreateOrder", func(t *testing.T) {
+	t.Run("ServiceList", func(t *testing.T) {
 		t.Parallel()
 		client := NewClient(Config{
 			ClientId:      "client_id",
@@ -74,35 +670,38 @@ func Test_Client(t *testing.T) {
 		client.timeNow = func() time.Time {
 			return currentTime
 		}
-		order := newOrder()
-		res, err := client.CreateOrder(&order)
+		res, err := client.ServiceList()
 		if err != nil {
 			t.Error(err)
 		}
-		if diff := cmp.Diff(*res, newOrderResponse()); diff != "" {
+		if diff := cmp.Diff(res, newServiceListResponse()); diff != "" {
 			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
 		}
 	})
-	t.Run("CreateOrder_Timeout", func(t *testing.T) {
+
+	t.Run("SearchCountry", func(t *testing.T) {
 		t.Parallel()
 		client := NewClient(Config{
 			ClientId:      "client_id",
 			ClientSecret:  "client_secret",
 			UseProduction: true,
-			Timeout:       100 * time.Millisecond,
+			Timeout:       10 * time.Second,
 		})
 		client.authURL = url + "/auth/token"
-		client.apiURL = url + "/v1/timeout"
+		client.apiURL = url + "/v1"
 		client.timeNow = func() time.Time {
 			return currentTime
 		}
-		order := newOrder()
-		_, err := client.CreateOrder(&order)
-		if diff := cmp.Diff(err.Error(), "Post \"http://"+server.Addr+"/v1/timeout/order\": context deadline exceeded (Client.Timeout exceeded while awaiting headers)"); diff != "" {
-			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		res, err := client.SearchCountry("Rom")
+		if err != nil {
+			t.Error(err)
+		}
+		if diff := cmp.Diff(res, newSearchCountryResponse()); diff != "" {
+			t.Errorf("Search response mismatch (-want +got):\n%s", diff)
 		}
 	})
-	t.Run("CreateOrder_UseAddressIds", func(t *testing.T) {
+
+	t.Run("SearchLocation", func(t *testing.T) {
 		t.Parallel()
 		client := NewClient(Config{
 			ClientId:      "client_id",
@@ -111,21 +710,20 @@ func Test_Client(t *testing.T) {
 			Timeout:       10 * time.Second,
 		})
 		client.authURL = url + "/auth/token"
-		client.apiURL = url + "/v1/order-with-address-id"
+		client.apiURL = url + "/v1"
 		client.timeNow = func() time.Time {
 			return currentTime
 		}
-		order := newOrderWithAddressId()
-		res, err := client.CreateOrder(&order)
+		res, err := client.SearchLocation("RO", "Cluj-Napoca")
 		if err != nil {
 			t.Error(err)
 		}
-		if diff := cmp.Diff(*res, newOrderResponse()); diff != "" {
-			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		if diff := cmp.Diff(res, newSearchLocationResponse()); diff != "" {
+			t.Errorf("Search response mismatch (-want +got):\n%s", diff)
 		}
 	})
 
-	t.Run("OrderOrice", func(t *testing.T) {
+	t.Run("SearchCity", func(t *testing.T) {
 		t.Parallel()
 		client := NewClient(Config{
 			ClientId:      "client_id",
@@ -138,16 +736,16 @@ func Test_Client(t *testing.T) {
 		client.timeNow = func() time.Time {
 			return currentTime
 		}
-		order := newOrder()
-		res, err := client.OrderPrice(&order)
+		res, err := client.SearchCity("RO", "Cluj", "Cluj Napoca")
 		if err != nil {
 			t.Error(err)
 		}
-		if diff := cmp.Diff(*res, newOrderPriceResponse()); diff != "" {
-			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		if diff := cmp.Diff(res, newSearchCityResponse()); diff != "" {
+			t.Errorf("Search response mismatch (-want +got):\n%s", diff)
 		}
 	})
-	t.Run("OrderPrice_UseAddressIds", func(t *testing.T) {
+
+	t.Run("SearchStreet", func(t *testing.T) {
 		t.Parallel()
 		client := NewClient(Config{
 			ClientId:      "client_id",
@@ -156,21 +754,20 @@ func Test_Client(t *testing.T) {
 			Timeout:       10 * time.Second,
 		})
 		client.authURL = url + "/auth/token"
-		client.apiURL = url + "/v1/order-with-address-id"
+		client.apiURL = url + "/v1"
 		client.timeNow = func() time.Time {
 			return currentTime
 		}
-		order := newOrderWithAddressId()
-		res, err := client.OrderPrice(&order)
+		res, err := client.SearchStreet("RO", "Cluj-Napoca", "Cluj", "1 Decembrie 1918/A")
 		if err != nil {
 			t.Error(err)
 		}
-		if diff := cmp.Diff(*res, newOrderPriceResponse()); diff != "" {
-			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		if diff := cmp.Diff(res, newSearchStreetResponse()); diff != "" {
+			t.Errorf("Search response mismatch (-want +got):\n%s", diff)
 		}
 	})
 
-	t.Run("OrderStatus", func(t *testing.T) {
+	t.Run("SearchPostalCode", func(t *testing.T) {
 		t.Parallel()
 		client := NewClient(Config{
 			ClientId:      "client_id",
@@ -183,17 +780,16 @@ func Test_Client(t *testing.T) {
 		client.timeNow = func() time.Time {
 			return currentTime
 		}
-		id := "id_1234"
-		res, err := client.OrderStatus(&id)
+		res, err := client.SearchPostalCode("RO", "Cluj-Napoca", "Cluj", "Str. 1 Decembrie 1918/A")
 		if err != nil {
 			t.Error(err)
 		}
-		if diff := cmp.Diff(*res, newOrderStatusResponse()); diff != "" {
-			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		if diff := cmp.Diff(res, newSearchPostalCodeResponse()); diff != "" {
+			t.Errorf("Search response mismatch (-want +got):\n%s", diff)
 		}
 	})
 
-	t.Run("AddressList", func(t *testing.T) {
+	t.Run("ValidatePostalCode", func(t *testing.T) {
 		t.Parallel()
 		client := NewClient(Config{
 			ClientId:      "client_id",
@@ -206,16 +802,16 @@ func Test_Client(t *testing.T) {
 		client.timeNow = func() time.Time {
 			return currentTime
 		}
-		res, err := client.AddressList(1)
+		res, err := client.ValidatePostalCode("RO", "Cluj-Napoca", "Cluj", "Str. 1 Decembrie 1918/A", "400001")
 		if err != nil {
 			t.Error(err)
 		}
-		if diff := cmp.Diff(*res, newAddressListResponse()); diff != "" {
-			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		if diff := cmp.Diff(*res, newValidatePostalCodeResponse()); diff != "" {
+			t.Errorf("Search response mismatch (-want +got):\n%s", diff)
 		}
 	})
 
-	t.Run("ServiceList", func(t *testing.T) {
+	t.Run("SearchPostalCodeReverse", func(t *testing.T) {
 		t.Parallel()
 		client := NewClient(Config{
 			ClientId:      "client_id",
@@ -228,12 +824,12 @@ func Test_Client(t *testing.T) {
 		client.timeNow = func() time.Time {
 			return currentTime
 		}
-		res, err := client.ServiceList()
+		res, err := client.SearchPostalCodeReverse("RO", "400001")
 		if err != nil {
 			t.Error(err)
 		}
-		if diff := cmp.Diff(res, newServiceListResponse()); diff != "" {
-			t.Errorf("Order response mismatch (-want +got):\n%s", diff)
+		if diff := cmp.Diff(res, newSearchPostalCodeReverseResponse()); diff != "" {
+			t.Errorf("Search response mismatch (-want +got):\n%s", diff)
 		}
 	})
 
@@ -260,6 +856,12 @@ func Test_Client(t *testing.T) {
 	})
 }
 
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
 func startServer() (*http.Server, error) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -277,9 +879,30 @@ func startServer() (*http.Server, error) {
 		statusPath,
 		http.StripPrefix(statusPath, http.HandlerFunc(orderStatusHandler)),
 	)
-	mux.HandleFunc("/v1/address", addressListHandler)
+	awbPath := "/v1/order/awb/"
+	mux.Handle(
+		awbPath,
+		http.StripPrefix(awbPath, http.HandlerFunc(orderAWBHandler)),
+	)
+	mux.HandleFunc("/v1/address", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			createAddressHandler(w, r)
+			return
+		}
+		addressListHandler(w, r)
+	})
+	addressPath := "/v1/address/"
+	mux.Handle(
+		addressPath,
+		http.StripPrefix(addressPath, http.HandlerFunc(addressHandler)),
+	)
 	mux.HandleFunc("/v1/service", serviceListHandler)
 	mux.HandleFunc("/v1/user/balance", userBalanceHandler)
+	mux.HandleFunc("/v1/search/", searchHandler)
+	mux.HandleFunc("/v1/unauthorized/user/balance", unauthorizedHandler)
+	mux.HandleFunc("/v1/flaky/user/balance", flakyUserBalanceHandler)
+	mux.HandleFunc("/v1/rate-limited/order", rateLimitedHandler)
+	mux.HandleFunc("/v1/unavailable/order", unavailableHandler)
 	server := &http.Server{
 		Addr:    ":9876",
 		Handler: mux,
@@ -309,6 +932,38 @@ func timeoutHandler(w http.ResponseWriter, r *http.Request) {
 	time.Sleep(100 * time.Millisecond)
 }
 
+func unauthorizedHandler(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&unauthorizedCalls, 1)
+	w.WriteHeader(http.StatusUnauthorized)
+	b, _ := json.Marshal(AuthResponseError{
+		Name:        "invalid_token",
+		Description: "Token revoked",
+	})
+	w.Write(b)
+}
+
+func flakyUserBalanceHandler(w http.ResponseWriter, r *http.Request) {
+	switch atomic.AddInt32(&flakyCalls, 1) {
+	case 1:
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	case 2:
+		w.WriteHeader(http.StatusBadGateway)
+	default:
+		userBalanceHandler(w, r)
+	}
+}
+
+func rateLimitedHandler(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&rateLimitedCalls, 1)
+	w.WriteHeader(http.StatusTooManyRequests)
+}
+
+func unavailableHandler(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&unavailableCalls, 1)
+	w.WriteHeader(http.StatusServiceUnavailable)
+}
+
 func authTokenHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
 		w.WriteHeader(http.StatusBadRequest)
@@ -580,6 +1235,39 @@ func orderStatusHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(b)
 }
 
+func orderAWBHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		b, _ := json.Marshal(AuthResponseError{
+			Name:        "invalid_request",
+			Description: "Invalid request: method must be GET",
+		})
+		w.Write(b)
+		return
+	}
+	authHeader := r.Header.Get("Authorization")
+	if authHeader != "Bearer "+getTestJWT(currentTime.Add(2*time.Hour).Unix()) {
+		w.WriteHeader(http.StatusUnauthorized)
+		b, _ := json.Marshal(AuthResponseError{
+			Name:        "invalid_token",
+			Description: "Invalid token",
+		})
+		w.Write(b)
+		return
+	}
+	if r.URL.Path != "id_1234" || r.URL.Query().Get("formatType") != "A6" {
+		w.WriteHeader(http.StatusBadRequest)
+		b, _ := json.Marshal(ResponseError{
+			Code:    400,
+			Message: "Invalid uniqueId",
+		})
+		w.Write(b)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Write(newOrderAWB())
+}
+
 func addressListHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -614,6 +1302,88 @@ func addressListHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(b)
 }
 
+func createAddressHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		b, _ := json.Marshal(AuthResponseError{
+			Name:        "invalid_request",
+			Description: "Invalid request: content must be application/json",
+		})
+		w.Write(b)
+		return
+	}
+	authHeader := r.Header.Get("Authorization")
+	if authHeader != "Bearer "+getTestJWT(currentTime.Add(2*time.Hour).Unix()) {
+		w.WriteHeader(http.StatusUnauthorized)
+		b, _ := json.Marshal(AuthResponseError{
+			Name:        "invalid_token",
+			Description: "Invalid token",
+		})
+		w.Write(b)
+		return
+	}
+	defer r.Body.Close()
+	var address OrderAddress
+	err := json.NewDecoder(r.Body).Decode(&address)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	address.AddressId = 1
+	if diff := cmp.Diff(address, newOrderAddress()); diff != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		b, _ := json.Marshal(ResponseError{
+			Message: "Invalid address",
+		})
+		w.Write(b)
+		return
+	}
+	b, _ := json.Marshal(address)
+	w.WriteHeader(http.StatusCreated)
+	w.Write(b)
+}
+
+func addressHandler(w http.ResponseWriter, r *http.Request) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader != "Bearer "+getTestJWT(currentTime.Add(2*time.Hour).Unix()) {
+		w.WriteHeader(http.StatusUnauthorized)
+		b, _ := json.Marshal(AuthResponseError{
+			Name:        "invalid_token",
+			Description: "Invalid token",
+		})
+		w.Write(b)
+		return
+	}
+	if r.URL.Path != "1" {
+		w.WriteHeader(http.StatusBadRequest)
+		b, _ := json.Marshal(ResponseError{
+			Message: "Address not found",
+		})
+		w.Write(b)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		b, _ := json.Marshal(newOrderAddress())
+		w.Write(b)
+	case http.MethodPut:
+		defer r.Body.Close()
+		var address OrderAddress
+		err := json.NewDecoder(r.Body).Decode(&address)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		address.AddressId = 1
+		b, _ := json.Marshal(address)
+		w.Write(b)
+	case http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
 func serviceListHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
@@ -662,6 +1432,54 @@ func userBalanceHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(b)
 }
 
+func searchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		b, _ := json.Marshal(AuthResponseError{
+			Name:        "invalid_request",
+			Description: "Invalid request: method must be GET",
+		})
+		w.Write(b)
+		return
+	}
+	authHeader := r.Header.Get("Authorization")
+	if authHeader != "Bearer "+getTestJWT(currentTime.Add(2*time.Hour).Unix()) {
+		w.WriteHeader(http.StatusUnauthorized)
+		b, _ := json.Marshal(AuthResponseError{
+			Name:        "invalid_token",
+			Description: "Invalid token",
+		})
+		w.Write(b)
+		return
+	}
+	var res interface{}
+	switch r.URL.EscapedPath() {
+	case "/v1/search/country/Rom":
+		res = newSearchCountryResponse()
+	case "/v1/search/location/RO/Cluj-Napoca":
+		res = newSearchLocationResponse()
+	case "/v1/search/city/RO/Cluj/Cluj%20Napoca":
+		res = newSearchCityResponse()
+	case "/v1/search/street/RO/Cluj-Napoca/Cluj/1%20Decembrie%201918%2FA":
+		res = newSearchStreetResponse()
+	case "/v1/search/postal-code/RO/Cluj-Napoca/Cluj/Str.%201%20Decembrie%201918%2FA":
+		res = newSearchPostalCodeResponse()
+	case "/v1/search/validate-postal-code/RO/Cluj-Napoca/Cluj/Str.%201%20Decembrie%201918%2FA/400001":
+		res = newValidatePostalCodeResponse()
+	case "/v1/search/postal-code-reverse/RO/400001":
+		res = newSearchPostalCodeReverseResponse()
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		b, _ := json.Marshal(ResponseError{
+			Message: "Invalid search path: " + r.URL.EscapedPath(),
+		})
+		w.Write(b)
+		return
+	}
+	b, _ := json.Marshal(res)
+	w.Write(b)
+}
+
 func newOrder() Order {
 	return Order{
 		Sender: Sender{
@@ -772,8 +1590,8 @@ func newOrderResponse() OrderResponse {
 	return OrderResponse{
 		Service: OrderResponseService{
 			Price: ServicePrice{
-				Total: 10.0,
-				NoVat: 8.0,
+				Total: NewMoney(1000, "RON"),
+				NoVat: NewMoney(800, "RON"),
 			},
 			Service: ServiceDetails{
 				Id:          1,
@@ -791,8 +1609,8 @@ func newOrderPriceResponse() OrderPriceResponse {
 	return OrderPriceResponse{
 		Selected: OrderResponseService{
 			Price: ServicePrice{
-				Total: 10.0,
-				NoVat: 8.0,
+				Total: NewMoney(1000, "RON"),
+				NoVat: NewMoney(800, "RON"),
 			},
 			Service: ServiceDetails{
 				Id:          1,
@@ -803,8 +1621,8 @@ func newOrderPriceResponse() OrderPriceResponse {
 		List: []OrderResponseService{
 			{
 				Price: ServicePrice{
-					Total: 10.0,
-					NoVat: 8.0,
+					Total: NewMoney(1000, "RON"),
+					NoVat: NewMoney(800, "RON"),
 				},
 				Service: ServiceDetails{
 					Id:          1,
@@ -841,6 +1659,11 @@ func newOrderStatusResponse() OrderStatusResponse {
 	}
 }
 
+// Larger than the 1MB limit applied to JSON responses.
+func newOrderAWB() []byte {
+	return bytes.Repeat([]byte("%PDF-1.4 awb_1234\n"), 1<<17)
+}
+
 func newAddressListResponse() AddressListResponse {
 	return AddressListResponse{
 		Data: []OrderAddress{
@@ -877,6 +1700,12 @@ func newAddressListResponse() AddressListResponse {
 	}
 }
 
+func newOrderAddress() OrderAddress {
+	address := newAddressListResponse().Data[0]
+	address.AddressId = 1
+	return address
+}
+
 func newServiceListResponse() []ServiceResponse {
 	return []ServiceResponse{
 		{
@@ -897,8 +1726,78 @@ func newServiceListResponse() []ServiceResponse {
 
 func newUserBalance() UserBalance {
 	return UserBalance{
-		Amount: 10.0,
-		Bonus:  10.0,
+		Amount: NewMoney(1000, "RON"),
+		Bonus:  NewMoney(1000, "RON"),
+	}
+}
+
+func newSearchCountryResponse() []SearchCountryResponse {
+	return []SearchCountryResponse{
+		{
+			Code: "RO",
+			Name: "Romania",
+		},
+	}
+}
+
+func newSearchLocationResponse() []SearchLocationResponse {
+	return []SearchLocationResponse{
+		{
+			CountryCode: "RO",
+			City:        "Cluj-Napoca",
+			County:      "Cluj",
+			CountyCode:  "CJ",
+			PostalCode:  "400001",
+		},
+	}
+}
+
+func newSearchCityResponse() []SearchCityResponse {
+	return []SearchCityResponse{
+		{
+			Name:       "Cluj-Napoca",
+			County:     "Cluj",
+			CountyCode: "CJ",
+		},
+	}
+}
+
+func newSearchStreetResponse() []SearchStreetResponse {
+	return []SearchStreetResponse{
+		{
+			Name:       "1 Decembrie 1918",
+			Type:       "Strada",
+			PostalCode: "400001",
+		},
+	}
+}
+
+func newSearchPostalCodeResponse() []SearchPostalCodeResponse {
+	return []SearchPostalCodeResponse{
+		{
+			PostalCode: "400001",
+			Street:     "Strada 1 Decembrie 1918",
+			Number:     "1-10",
+		},
+	}
+}
+
+func newValidatePostalCodeResponse() ValidatePostalCodeResponse {
+	return ValidatePostalCodeResponse{
+		Valid: true,
+	}
+}
+
+func newSearchPostalCodeReverseResponse() []SearchPostalCodeReverseResponse {
+	return []SearchPostalCodeReverseResponse{
+		{
+			CountryCode: "RO",
+			PostalCode:  "400001",
+			City:        "Cluj-Napoca",
+			County:      "Cluj",
+			CountyCode:  "CJ",
+			Street:      "Strada 1 Decembrie 1918",
+		},
 	}
 }
 
@@ -909,3 +1808,31 @@ func getTestJWT(exp int64) string {
 		) +
 		".signature"
 }
+
+func Test_RateLimiter(t *testing.T) {
+	client := NewClient(Config{})
+	now := currentTime
+	client.timeNow = func() time.Time {
+		return now
+	}
+	var waits []time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	limiter := &rateLimiter{client: client, interval: 250 * time.Millisecond}
+	for i := 0; i < 3; i++ {
+		err := limiter.wait(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(time.Second)
+	err := limiter.wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(waits, []time.Duration{250 * time.Millisecond, 500 * time.Millisecond}); diff != "" {
+		t.Errorf("Waits mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/radulucut/coleteonline"
+	"gopkg.in/yaml.v3"
+)
+
+// The config file, in JSON or YAML. Environment variables override its values.
+type fileConfig struct {
+	ClientId     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+	Production   bool   `json:"production"`
+	AuthURL      string `json:"authURL"`
+	APIURL       string `json:"apiURL"`
+	Language     string `json:"language"`
+}
+
+// The path of the config file when -config is not set: $COLETEONLINE_CONFIG, or
+// coleteonline/config.json in the user config directory when it exists.
+func defaultConfigPath(getenv func(string) string) string {
+	if path := getenv("COLETEONLINE_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(dir, "coleteonline", "config.json")
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+func loadConfig(path string, getenv func(string) string) (coleteonline.Config, error) {
+	var config fileConfig
+	if path == "" {
+		path = defaultConfigPath(getenv)
+	}
+	if path != "" {
+		err := decodeFile(path, &config)
+		if err != nil {
+			return coleteonline.Config{}, fmt.Errorf("reading config: %w", err)
+		}
+	}
+	for _, env := range []struct {
+		name  string
+		value *string
+	}{
+		{"COLETEONLINE_CLIENT_ID", &config.ClientId},
+		{"COLETEONLINE_CLIENT_SECRET", &config.ClientSecret},
+		{"COLETEONLINE_AUTH_URL", &config.AuthURL},
+		{"COLETEONLINE_API_URL", &config.APIURL},
+		{"COLETEONLINE_LANGUAGE", &config.Language},
+	} {
+		if v := getenv(env.name); v != "" {
+			*env.value = v
+		}
+	}
+	if v := getenv("COLETEONLINE_PRODUCTION"); v != "" {
+		production, err := strconv.ParseBool(v)
+		if err != nil {
+			return coleteonline.Config{}, fmt.Errorf("COLETEONLINE_PRODUCTION: %q is not a valid boolean", v)
+		}
+		config.Production = production
+	}
+	if config.ClientId == "" || config.ClientSecret == "" {
+		return coleteonline.Config{}, errors.New("missing credentials, set COLETEONLINE_CLIENT_ID and COLETEONLINE_CLIENT_SECRET or use a config file")
+	}
+	return coleteonline.Config{
+		ClientId:      config.ClientId,
+		ClientSecret:  config.ClientSecret,
+		UseProduction: config.Production,
+		AuthURL:       config.AuthURL,
+		APIURL:        config.APIURL,
+		Language:      config.Language,
+		Timeout:       30 * time.Second,
+	}, nil
+}
+
+// Decodes a JSON or, for .yaml and .yml files, a YAML file. YAML is converted to JSON first
+// so the json tags of the library types apply to both.
+func decodeFile(path string, v interface{}) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var doc interface{}
+		err = yaml.Unmarshal(b, &doc)
+		if err != nil {
+			return err
+		}
+		b, err = json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,12 @@
+module github.com/radulucut/coleteonline/cmd/coleteonline
+
+go 1.17
+
+require (
+	github.com/google/go-cmp v0.6.0
+	github.com/radulucut/coleteonline v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+// The command is built against the library in this repository.
+replace github.com/radulucut/coleteonline => ../..
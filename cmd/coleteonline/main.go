@@ -0,0 +1,343 @@
+// Command coleteonline prices, creates and tracks orders using the Colete Online API.
+//
+// Credentials are read from the COLETEONLINE_CLIENT_ID and COLETEONLINE_CLIENT_SECRET
+// environment variables or from a JSON or YAML config file, see -config.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/radulucut/coleteonline"
+)
+
+const usage = `Usage: coleteonline <command> [flags] [args]
+
+Commands:
+  price <order file>       List the offers for an order
+  create <order file>      Create an order
+  status <uniqueId|awb>    Show the status history of an order
+  awb <uniqueId>           Download the AWB of an order
+  addresses                List the saved addresses
+  services                 List the available services
+  balance                  Show the account balance
+
+Order files are JSON or YAML (.yaml, .yml) with the same fields as the API.
+Run "coleteonline <command> -h" for the flags of a command.
+`
+
+type command struct {
+	run     func(ctx context.Context, env *env, args []string) error
+	minArgs int
+	maxArgs int
+	args    string
+}
+
+var commands = map[string]command{
+	"price":     {run: runPrice, minArgs: 1, maxArgs: 1, args: "<order file>"},
+	"create":    {run: runCreate, minArgs: 1, maxArgs: 1, args: "<order file>"},
+	"status":    {run: runStatus, minArgs: 1, maxArgs: 1, args: "<uniqueId|awb>"},
+	"awb":       {run: runAWB, minArgs: 1, maxArgs: 1, args: "<uniqueId>"},
+	"addresses": {run: runAddresses},
+	"services":  {run: runServices},
+	"balance":   {run: runBalance},
+}
+
+// Shared by the commands.
+type env struct {
+	client *coleteonline.Client
+	stdout io.Writer
+	json   bool
+	lang   string
+	flags  *commandFlags
+}
+
+type commandFlags struct {
+	awbFormat string
+	awbOutput string
+	page      int64
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, os.Getenv))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer, getenv func(string) string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		fmt.Fprint(stderr, usage)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "coleteonline: unknown command %q\n\n%s", name, usage)
+		return 2
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "config file, defaults to $COLETEONLINE_CONFIG or coleteonline/config.json in the user config directory")
+	jsonOutput := fs.Bool("json", false, "print JSON instead of a table")
+	flags := &commandFlags{}
+	switch name {
+	case "awb":
+		fs.StringVar(&flags.awbFormat, "format", string(coleteonline.AWBFormatA4), "page format, A4 or A6")
+		fs.StringVar(&flags.awbOutput, "o", "", "output file, defaults to <uniqueId>.pdf")
+	case "addresses":
+		fs.Int64Var(&flags.page, "page", 0, "only list this page, all pages are listed when 0")
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: coleteonline %s [flags] %s\n\nFlags:\n", name, cmd.args)
+		fs.PrintDefaults()
+	}
+	err := fs.Parse(args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+	if fs.NArg() < cmd.minArgs || fs.NArg() > cmd.maxArgs {
+		fs.Usage()
+		return 2
+	}
+	config, err := loadConfig(*configPath, getenv)
+	if err != nil {
+		fmt.Fprintf(stderr, "coleteonline: %s\n", err)
+		return 1
+	}
+	lang := config.Language
+	if lang == "" {
+		lang = "ro"
+	}
+	e := &env{
+		client: coleteonline.NewClient(config),
+		stdout: stdout,
+		json:   *jsonOutput,
+		lang:   lang,
+		flags:  flags,
+	}
+	err = cmd.run(context.Background(), e, fs.Args())
+	if err != nil {
+		printError(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// Validation errors are listed one per line below the message.
+func printError(w io.Writer, err error) {
+	fmt.Fprintf(w, "coleteonline: %s\n", err)
+	var rErr *coleteonline.ResponseError
+	if errors.As(err, &rErr) {
+		for _, e := range rErr.Errors {
+			fmt.Fprintf(w, "  %s: %s\n", e.Parameter, e.Message)
+		}
+	}
+}
+
+func readOrder(path string) (*coleteonline.Order, error) {
+	var order coleteonline.Order
+	err := decodeFile(path, &order)
+	if err != nil {
+		return nil, fmt.Errorf("reading order: %w", err)
+	}
+	return &order, nil
+}
+
+func runPrice(ctx context.Context, e *env, args []string) error {
+	order, err := readOrder(args[0])
+	if err != nil {
+		return err
+	}
+	res, err := e.client.OrderPriceWithContext(ctx, order)
+	if err != nil {
+		return err
+	}
+	if e.json {
+		return e.printJSON(res)
+	}
+	rows := make([][]string, len(res.List))
+	for i, s := range res.List {
+		selected := ""
+		if s.Service.Id == res.Selected.Service.Id {
+			selected = "*"
+		}
+		rows[i] = []string{
+			selected,
+			strconv.FormatInt(s.Service.Id, 10),
+			s.Service.CourierName,
+			s.Service.Name,
+			s.Price.Total.Decimal(),
+			s.Price.NoVat.Decimal(),
+		}
+	}
+	return e.printTable([]string{"", "ID", "COURIER", "SERVICE", "TOTAL", "NO VAT"}, rows)
+}
+
+func runCreate(ctx context.Context, e *env, args []string) error {
+	order, err := readOrder(args[0])
+	if err != nil {
+		return err
+	}
+	res, err := e.client.CreateOrderWithContext(ctx, order)
+	if err != nil {
+		return err
+	}
+	if e.json {
+		return e.printJSON(res)
+	}
+	return e.printTable(
+		[]string{"UNIQUE ID", "AWB", "COURIER", "SERVICE", "TOTAL", "PICKUP"},
+		[][]string{{
+			res.UniqueId,
+			res.AWB,
+			res.Service.Service.CourierName,
+			res.Service.Service.Name,
+			res.Service.Price.Total.Decimal(),
+			res.EstimatedPickupDate,
+		}},
+	)
+}
+
+func runStatus(ctx context.Context, e *env, args []string) error {
+	res, err := e.client.OrderStatusWithContext(ctx, &args[0])
+	if err != nil {
+		return err
+	}
+	if e.json {
+		return e.printJSON(res)
+	}
+	rows := make([][]string, len(res.History))
+	for i := range res.History {
+		h := &res.History[i]
+		text := h.Text(e.lang, nil)
+		rows[i] = []string{
+			h.DateTime.Format("2006-01-02 15:04"),
+			h.Code.String(),
+			text.Name,
+			text.Reason,
+		}
+	}
+	return e.printTable([]string{"DATE", "CODE", "STATUS", "REASON"}, rows)
+}
+
+func runAWB(ctx context.Context, e *env, args []string) error {
+	format := coleteonline.AWBFormat(e.flags.awbFormat)
+	if format != coleteonline.AWBFormatA4 && format != coleteonline.AWBFormatA6 {
+		return fmt.Errorf("unknown AWB format %q", e.flags.awbFormat)
+	}
+	name := e.flags.awbOutput
+	if name == "" {
+		// The id comes from the user, so it must not lead outside the current directory
+		if strings.ContainsAny(args[0], `/\`) || filepath.Base(args[0]) != args[0] {
+			return fmt.Errorf("%q cannot be used as a file name, set -o", args[0])
+		}
+		name = args[0] + ".pdf"
+	}
+	err := e.client.OrderAWBToFileWithContext(ctx, &args[0], format, name)
+	if err != nil {
+		return err
+	}
+	if e.json {
+		return e.printJSON(map[string]string{"file": name})
+	}
+	fmt.Fprintln(e.stdout, name)
+	return nil
+}
+
+func runAddresses(ctx context.Context, e *env, args []string) error {
+	var addresses []coleteonline.OrderAddress
+	if e.flags.page > 0 {
+		res, err := e.client.AddressListWithContext(ctx, e.flags.page)
+		if err != nil {
+			return err
+		}
+		addresses = res.Data
+	} else {
+		var err error
+		addresses, err = e.client.AllAddresses(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if e.json {
+		if addresses == nil {
+			addresses = []coleteonline.OrderAddress{}
+		}
+		return e.printJSON(addresses)
+	}
+	rows := make([][]string, len(addresses))
+	for i, a := range addresses {
+		rows[i] = []string{
+			strconv.FormatInt(a.AddressId, 10),
+			a.Contact.Name,
+			a.Contact.Company,
+			a.Contact.Phone,
+			a.Address.City,
+			a.Address.Street,
+			a.Address.Number,
+		}
+	}
+	return e.printTable([]string{"ID", "NAME", "COMPANY", "PHONE", "CITY", "STREET", "NUMBER"}, rows)
+}
+
+func runServices(ctx context.Context, e *env, args []string) error {
+	res, err := e.client.ServiceListWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	if e.json {
+		return e.printJSON(res)
+	}
+	rows := make([][]string, len(res))
+	for i, s := range res {
+		rows[i] = []string{strconv.FormatInt(s.Id, 10), s.CourierName, s.Name}
+	}
+	return e.printTable([]string{"ID", "COURIER", "NAME"}, rows)
+}
+
+func runBalance(ctx context.Context, e *env, args []string) error {
+	res, err := e.client.UserBalanceWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	if e.json {
+		return e.printJSON(res)
+	}
+	return e.printTable(
+		[]string{"AMOUNT", "BONUS"},
+		[][]string{{res.Amount.Decimal(), res.Bonus.Decimal()}},
+	)
+}
+
+func (e *env) printJSON(v interface{}) error {
+	encoder := json.NewEncoder(e.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func (e *env) printTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			if i > 0 {
+				fmt.Fprint(w, "\t")
+			}
+			fmt.Fprint(w, cell)
+		}
+		fmt.Fprintln(w)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/radulucut/coleteonline"
+	"github.com/radulucut/coleteonline/coleteonlinetest"
+)
+
+const orderYAML = `sender:
+  addressId: 1
+recipient:
+  addressId: 2
+packages:
+  type: 2
+  content: Content
+  list:
+    - weight: 1
+      width: 1
+      height: 1
+      length: 1
+service:
+  selectionType: directId
+  serviceIds: [1]
+`
+
+func Test_Run(t *testing.T) {
+	server := coleteonlinetest.NewServer()
+	defer server.Close()
+	dir := t.TempDir()
+	orderFile := filepath.Join(dir, "order.yaml")
+	err := os.WriteFile(orderFile, []byte(orderYAML), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	getenv := func(name string) string {
+		return map[string]string{
+			"COLETEONLINE_CLIENT_ID":     "client_id",
+			"COLETEONLINE_CLIENT_SECRET": "client_secret",
+			"COLETEONLINE_AUTH_URL":      server.AuthURL(),
+			"COLETEONLINE_API_URL":       server.APIURL(),
+		}[name]
+	}
+	exec := func(args ...string) (string, string, int) {
+		var stdout, stderr bytes.Buffer
+		code := run(args, &stdout, &stderr, getenv)
+		return stdout.String(), stderr.String(), code
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		stdout, stderr, code := exec("create", "-json", orderFile)
+		if code != 0 {
+			t.Fatalf("Exit code %d: %s", code, stderr)
+		}
+		var res coleteonline.OrderResponse
+		err := json.Unmarshal([]byte(stdout), &res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(server.Orders(), []coleteonline.OrderResponse{res}); diff != "" {
+			t.Errorf("Orders mismatch (-want +got):\n%s", diff)
+		}
+
+		stdout, stderr, code = exec("status", res.UniqueId)
+		if code != 0 {
+			t.Fatalf("Exit code %d: %s", code, stderr)
+		}
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if diff := cmp.Diff(len(lines), 2); diff != "" {
+			t.Fatalf("Lines mismatch (-want +got):\n%s", diff)
+		}
+		if !strings.Contains(lines[1], "registered") || !strings.Contains(lines[1], "Comanda inregistrata") {
+			t.Errorf("Unexpected status line: %q", lines[1])
+		}
+	})
+
+	t.Run("Balance", func(t *testing.T) {
+		stdout, stderr, code := exec("balance")
+		if code != 0 {
+			t.Fatalf("Exit code %d: %s", code, stderr)
+		}
+		if diff := cmp.Diff(stdout, "AMOUNT   BONUS\n1000.00  0.00\n"); diff != "" {
+			t.Errorf("Output mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		invalid := filepath.Join(dir, "invalid.json")
+		err := os.WriteFile(invalid, []byte(`{"service":{"selectionType":"bestPrice"}}`), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, stderr, code := exec("price", invalid)
+		if diff := cmp.Diff(code, 1); diff != "" {
+			t.Errorf("Exit code mismatch (-want +got):\n%s", diff)
+		}
+		if !strings.Contains(stderr, "  sender.addressId: ") {
+			t.Errorf("Expected field errors, got %q", stderr)
+		}
+	})
+
+	t.Run("AWBFileName", func(t *testing.T) {
+		_, stderr, code := exec("awb", "../order")
+		if diff := cmp.Diff(code, 1); diff != "" {
+			t.Errorf("Exit code mismatch (-want +got):\n%s", diff)
+		}
+		if !strings.Contains(stderr, "set -o") {
+			t.Errorf("Expected file name error, got %q", stderr)
+		}
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		_, _, code := exec("unknown")
+		if diff := cmp.Diff(code, 2); diff != "" {
+			t.Errorf("Exit code mismatch (-want +got):\n%s", diff)
+		}
+		_, _, code = exec("status")
+		if diff := cmp.Diff(code, 2); diff != "" {
+			t.Errorf("Exit code mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func Test_LoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	err := os.WriteFile(path, []byte("clientId: file_id\nclientSecret: file_secret\nproduction: true\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadConfig(path, func(name string) string {
+		if name == "COLETEONLINE_CLIENT_SECRET" {
+			return "env_secret"
+		}
+		return ""
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]interface{}{config.ClientId, config.ClientSecret, config.UseProduction}, []interface{}{"file_id", "env_secret", true}); diff != "" {
+		t.Errorf("Config mismatch (-want +got):\n%s", diff)
+	}
+
+	_, err = loadConfig("", func(string) string { return "" })
+	if err == nil {
+		t.Error("Expected an error for missing credentials")
+	}
+}
@@ -0,0 +1,728 @@
+// Package coleteonlinetest provides an in-memory fake of the Colete Online API for tests.
+package coleteonlinetest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/radulucut/coleteonline"
+)
+
+const (
+	DefaultClientId     = "client_id"
+	DefaultClientSecret = "client_secret"
+)
+
+// Failure is returned instead of the regular response of an endpoint.
+type Failure struct {
+	Status int
+	// Marshaled as JSON when set.
+	Body interface{}
+	// Number of requests to fail, 0 fails all of them until ClearFailures is called.
+	Times int
+}
+
+type order struct {
+	order    coleteonline.Order
+	response coleteonline.OrderResponse
+	history  []coleteonline.StatusHistory
+}
+
+type Server struct {
+	server       *httptest.Server
+	clientId     string
+	clientSecret string
+
+	mu           sync.Mutex
+	now          func() time.Time
+	tokenTTL     time.Duration
+	tokens       map[string]time.Time
+	tokenCount   int
+	latency      time.Duration
+	failures     map[string]*Failure
+	orders       map[string]*order
+	orderIds     []string
+	services     []coleteonline.ServiceResponse
+	prices       map[int64]coleteonline.ServicePrice
+	addresses    []coleteonline.OrderAddress
+	pageSize     int
+	balance      coleteonline.UserBalance
+	awb          []byte
+	requestCount map[string]int
+}
+
+// NewServer starts a fake server accepting DefaultClientId and DefaultClientSecret.
+// The caller should call Close when finished.
+func NewServer() *Server {
+	return NewServerWithCredentials(DefaultClientId, DefaultClientSecret)
+}
+
+func NewServerWithCredentials(clientId string, clientSecret string) *Server {
+	s := &Server{
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		now:          time.Now,
+		tokenTTL:     2 * time.Hour,
+		tokens:       make(map[string]time.Time),
+		failures:     make(map[string]*Failure),
+		orders:       make(map[string]*order),
+		prices:       make(map[int64]coleteonline.ServicePrice),
+		pageSize:     10,
+		requestCount: make(map[string]int),
+		services: []coleteonline.ServiceResponse{
+			{
+				Id:          1,
+				CourierName: "Courier",
+				Name:        "Standard",
+				ExtraOptions: []coleteonline.ServiceExtraOption{
+					{
+						Id:   int64(coleteonline.ExtraOptionIdOpenAtDelivery),
+						Name: "Open at delivery",
+					},
+				},
+			},
+		},
+		balance: coleteonline.UserBalance{
+			Amount: coleteonline.NewMoney(100000, coleteonline.DefaultCurrency),
+			Bonus:  coleteonline.NewMoney(0, coleteonline.DefaultCurrency),
+		},
+		awb: []byte("%PDF-1.4\n"),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", s.handleToken)
+	mux.HandleFunc("/v1/order", s.authorized("/order", s.handleCreateOrder))
+	mux.HandleFunc("/v1/order/price", s.authorized("/order/price", s.handleOrderPrice))
+	mux.HandleFunc("/v1/order/status/", s.authorized("/order/status", s.handleOrderStatus))
+	mux.HandleFunc("/v1/order/awb/", s.authorized("/order/awb", s.handleOrderAWB))
+	mux.HandleFunc("/v1/address", s.authorized("/address", s.handleAddressList))
+	mux.HandleFunc("/v1/address/", s.authorized("/address", s.handleAddress))
+	mux.HandleFunc("/v1/service", s.authorized("/service", s.handleServiceList))
+	mux.HandleFunc("/v1/user/balance", s.authorized("/user/balance", s.handleUserBalance))
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// The base URL of the fake.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// The URL to use as the auth token endpoint.
+func (s *Server) AuthURL() string {
+	return s.server.URL + "/token"
+}
+
+// The URL to use as the API base, paths such as /order are appended to it.
+func (s *Server) APIURL() string {
+	return s.server.URL + "/v1"
+}
+
+// A client config pointing at the fake.
+func (s *Server) Config() coleteonline.Config {
+	return coleteonline.Config{
+		ClientId:     s.clientId,
+		ClientSecret: s.clientSecret,
+		AuthURL:      s.AuthURL(),
+		APIURL:       s.APIURL(),
+		HTTPClient:   s.server.Client(),
+	}
+}
+
+func (s *Server) SetNow(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+func (s *Server) SetTokenTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokenTTL = ttl
+}
+
+// Invalidates all the issued tokens, API requests using them receive 401.
+func (s *Server) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = make(map[string]time.Time)
+}
+
+// Delays every response, including the auth token one.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// Fails the requests for path, which is either "/token" or an API path such as "/order/price".
+func (s *Server) Fail(path string, failure Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[path] = &failure
+}
+
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = make(map[string]*Failure)
+}
+
+// Number of requests received for path, counting failed ones.
+func (s *Server) RequestCount(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requestCount[path]
+}
+
+func (s *Server) SetServices(services []coleteonline.ServiceResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.services = services
+}
+
+// Prices default to a total of 20 RON and 16.81 RON without VAT.
+func (s *Server) SetPrice(serviceId int64, price coleteonline.ServicePrice) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prices[serviceId] = price
+}
+
+// The saved addresses, which can be managed through the address endpoints.
+func (s *Server) Addresses() []coleteonline.OrderAddress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]coleteonline.OrderAddress, len(s.addresses))
+	copy(res, s.addresses)
+	return res
+}
+
+// A pageSize lower than 1 keeps the default of 10.
+func (s *Server) SetAddresses(addresses []coleteonline.OrderAddress, pageSize int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addresses = make([]coleteonline.OrderAddress, len(addresses))
+	copy(s.addresses, addresses)
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	s.pageSize = pageSize
+}
+
+func (s *Server) SetBalance(balance coleteonline.UserBalance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance = balance
+}
+
+func (s *Server) SetAWB(awb []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.awb = awb
+}
+
+// The created orders, in creation order.
+func (s *Server) Orders() []coleteonline.OrderResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]coleteonline.OrderResponse, 0, len(s.orderIds))
+	for _, id := range s.orderIds {
+		res = append(res, s.orders[id].response)
+	}
+	return res
+}
+
+// Returns the order as it was submitted.
+func (s *Server) Order(uniqueIdOrAWB string) (*coleteonline.Order, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := s.findOrder(uniqueIdOrAWB)
+	if o == nil {
+		return nil, false
+	}
+	res := o.order
+	return &res, true
+}
+
+// Appends a status to the order history. DateTime and UnixDateTime default to the server time.
+func (s *Server) AdvanceStatus(uniqueIdOrAWB string, status coleteonline.StatusHistory) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := s.findOrder(uniqueIdOrAWB)
+	if o == nil {
+		return fmt.Errorf("order not found: %s", uniqueIdOrAWB)
+	}
+	if status.DateTime.IsZero() && status.UnixDateTime == 0 {
+		status.DateTime = s.now().Truncate(time.Second)
+	}
+	if status.UnixDateTime == 0 {
+		status.UnixDateTime = status.DateTime.Unix()
+	}
+	if status.DateTime.IsZero() {
+		status.DateTime = time.Unix(status.UnixDateTime, 0)
+	}
+	o.history = append(o.history, status)
+	return nil
+}
+
+func (s *Server) findOrder(uniqueIdOrAWB string) *order {
+	if o, ok := s.orders[uniqueIdOrAWB]; ok {
+		return o
+	}
+	for _, o := range s.orders {
+		if o.response.AWB == uniqueIdOrAWB {
+			return o
+		}
+	}
+	return nil
+}
+
+// Applies latency and failures, returning false when the request was already answered.
+func (s *Server) intercept(path string, w http.ResponseWriter, r *http.Request) bool {
+	s.mu.Lock()
+	s.requestCount[path]++
+	latency := s.latency
+	failure := s.failures[path]
+	var res Failure
+	if failure != nil {
+		res = *failure
+		if failure.Times > 0 {
+			failure.Times--
+			if failure.Times == 0 {
+				delete(s.failures, path)
+			}
+		}
+	}
+	s.mu.Unlock()
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+	if failure == nil {
+		return true
+	}
+	if res.Body != nil {
+		writeJSON(w, res.Status, res.Body)
+	} else {
+		w.WriteHeader(res.Status)
+	}
+	return false
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if !s.intercept("/token", w, r) {
+		return
+	}
+	if r.Method != http.MethodPost ||
+		r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		writeJSON(w, http.StatusBadRequest, coleteonline.AuthResponseError{
+			Name:        "invalid_request",
+			Description: "Invalid request: content must be application/x-www-form-urlencoded",
+		})
+		return
+	}
+	if r.Header.Get("Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte(s.clientId+":"+s.clientSecret)) {
+		writeJSON(w, http.StatusUnauthorized, coleteonline.AuthResponseError{
+			Name:        "invalid_client",
+			Description: "Invalid client credentials",
+		})
+		return
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil || string(b) != "grant_type=client_credentials" {
+		writeJSON(w, http.StatusBadRequest, coleteonline.AuthResponseError{
+			Name:        "invalid_request",
+			Description: "Missing parameter: `grant_type`",
+		})
+		return
+	}
+	s.mu.Lock()
+	s.tokenCount++
+	exp := s.now().Add(s.tokenTTL)
+	token := "header." +
+		base64.RawURLEncoding.EncodeToString(
+			[]byte(fmt.Sprintf(`{"exp":%d,"jti":"%d"}`, exp.Unix(), s.tokenCount)),
+		) +
+		".signature"
+	s.tokens[token] = exp
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, coleteonline.AuthToken{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.tokenTTL / time.Second),
+	})
+}
+
+func (s *Server) authorized(path string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.intercept(path, w, r) {
+			return
+		}
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		s.mu.Lock()
+		exp, ok := s.tokens[token]
+		valid := ok && s.now().Before(exp)
+		s.mu.Unlock()
+		if !valid {
+			writeJSON(w, http.StatusUnauthorized, coleteonline.AuthResponseError{
+				Name:        "invalid_token",
+				Description: "Invalid token",
+			})
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleCreateOrder(w http.ResponseWriter, r *http.Request) {
+	o, ok := decodeOrder(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	selected, errs := s.selectService(o)
+	if errs != nil {
+		writeValidationError(w, errs)
+		return
+	}
+	n := len(s.orderIds) + 1
+	now := s.now()
+	res := coleteonline.OrderResponse{
+		Service:             selected,
+		AWB:                 fmt.Sprintf("AWB%010d", n),
+		UniqueId:            fmt.Sprintf("order_%d", n),
+		EstimatedPickupDate: now.Add(24 * time.Hour).Format("2006-01-02"),
+	}
+	s.orders[res.UniqueId] = &order{
+		order:    *o,
+		response: res,
+		history: []coleteonline.StatusHistory{
+			{
+				DateTime:     now.Truncate(time.Second),
+				UnixDateTime: now.Unix(),
+				StatusTextParts: coleteonline.StatusTextParts{
+					Ro: coleteonline.StatusTextPart{
+						Name: "Comanda inregistrata",
+					},
+				},
+				Code: coleteonline.StatusCodeRegistered,
+			},
+		},
+	}
+	s.orderIds = append(s.orderIds, res.UniqueId)
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) handleOrderPrice(w http.ResponseWriter, r *http.Request) {
+	o, ok := decodeOrder(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	selected, errs := s.selectService(o)
+	if errs != nil {
+		writeValidationError(w, errs)
+		return
+	}
+	writeJSON(w, http.StatusOK, coleteonline.OrderPriceResponse{
+		Selected: selected,
+		List:     s.quote(o),
+	})
+}
+
+func (s *Server) handleOrderStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/v1/order/status/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := s.findOrder(id)
+	if o == nil {
+		writeJSON(w, http.StatusBadRequest, coleteonline.ResponseError{
+			Message: "Order not found",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	history := make([]coleteonline.StatusHistory, len(o.history))
+	copy(history, o.history)
+	writeJSON(w, http.StatusOK, coleteonline.OrderStatusResponse{
+		Summary: coleteonline.StatusSummary{
+			UniqueId: o.response.UniqueId,
+			AWB:      o.response.AWB,
+		},
+		History: history,
+	})
+}
+
+func (s *Server) handleOrderAWB(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/v1/order/awb/")
+	s.mu.Lock()
+	o := s.findOrder(id)
+	awb := s.awb
+	s.mu.Unlock()
+	if o == nil {
+		writeJSON(w, http.StatusBadRequest, coleteonline.ResponseError{
+			Message: "Order not found",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Length", strconv.Itoa(len(awb)))
+	w.Write(awb)
+}
+
+func (s *Server) handleAddressList(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		s.handleCreateAddress(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	page, err := strconv.ParseInt(r.URL.Query().Get("page"), 10, 64)
+	if err != nil || page < 1 {
+		writeValidationError(w, []coleteonline.Error{
+			{Parameter: "page", Message: "must be a positive number"},
+		})
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pageSize := int64(s.pageSize)
+	total := int64(len(s.addresses))
+	totalPages := (total + pageSize - 1) / pageSize
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	data := make([]coleteonline.OrderAddress, end-start)
+	copy(data, s.addresses[start:end])
+	writeJSON(w, http.StatusOK, coleteonline.AddressListResponse{
+		Data: data,
+		Pagination: coleteonline.Pagination{
+			TotalItems:  total,
+			CurrentPage: page,
+			TotalPages:  totalPages,
+		},
+	})
+}
+
+func (s *Server) handleCreateAddress(w http.ResponseWriter, r *http.Request) {
+	var address coleteonline.OrderAddress
+	err := json.NewDecoder(r.Body).Decode(&address)
+	if err != nil {
+		writeValidationError(w, []coleteonline.Error{
+			{Parameter: "body", Message: err.Error()},
+		})
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	address.AddressId = 1
+	for _, a := range s.addresses {
+		if a.AddressId >= address.AddressId {
+			address.AddressId = a.AddressId + 1
+		}
+	}
+	s.addresses = append(s.addresses, address)
+	writeJSON(w, http.StatusOK, address)
+}
+
+func (s *Server) handleAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/v1/address/"), 10, 64)
+	if err != nil {
+		writeValidationError(w, []coleteonline.Error{
+			{Parameter: "addressId", Message: "must be a number"},
+		})
+		return
+	}
+	var address coleteonline.OrderAddress
+	if r.Method == http.MethodPut {
+		err = json.NewDecoder(r.Body).Decode(&address)
+		if err != nil {
+			writeValidationError(w, []coleteonline.Error{
+				{Parameter: "body", Message: err.Error()},
+			})
+			return
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := -1
+	for j := range s.addresses {
+		if s.addresses[j].AddressId == id {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		writeJSON(w, http.StatusBadRequest, coleteonline.ResponseError{
+			Message: "Address not found",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.addresses[i])
+	case http.MethodPut:
+		address.AddressId = id
+		s.addresses[i] = address
+		writeJSON(w, http.StatusOK, address)
+	case http.MethodDelete:
+		s.addresses = append(s.addresses[:i:i], s.addresses[i+1:]...)
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleServiceList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.services)
+}
+
+func (s *Server) handleUserBalance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.balance)
+}
+
+// Must be called with the lock held.
+func (s *Server) quote(o *coleteonline.Order) []coleteonline.OrderResponseService {
+	var res []coleteonline.OrderResponseService
+	for _, service := range s.services {
+		if o.Service.SelectionType == coleteonline.ServiceTypeDirectId && !containsId(o.Service.ServiceIds, service.Id) {
+			continue
+		}
+		if o.Service.SelectionType == coleteonline.ServiceTypeBestPrice &&
+			len(o.Service.ServiceIds) != 0 &&
+			!containsId(o.Service.ServiceIds, service.Id) {
+			continue
+		}
+		price, ok := s.prices[service.Id]
+		if !ok {
+			price = coleteonline.ServicePrice{
+				Total: coleteonline.NewMoney(2000, coleteonline.DefaultCurrency),
+				NoVat: coleteonline.NewMoney(1681, coleteonline.DefaultCurrency),
+			}
+		}
+		res = append(res, coleteonline.OrderResponseService{
+			Price: price,
+			Service: coleteonline.ServiceDetails{
+				Id:          service.Id,
+				CourierName: service.CourierName,
+				Name:        service.Name,
+			},
+		})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Price.Total.Amount < res[j].Price.Total.Amount
+	})
+	return res
+}
+
+// Must be called with the lock held.
+func (s *Server) selectService(o *coleteonline.Order) (coleteonline.OrderResponseService, []coleteonline.Error) {
+	if errs := o.Validate(); errs != nil {
+		return coleteonline.OrderResponseService{}, errs
+	}
+	list := s.quote(o)
+	if len(list) == 0 {
+		return coleteonline.OrderResponseService{}, []coleteonline.Error{
+			{Parameter: "service", Message: "no service available for this order"},
+		}
+	}
+	return list[0], nil
+}
+
+func decodeOrder(w http.ResponseWriter, r *http.Request) (*coleteonline.Order, bool) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	if r.Header.Get("Content-Type") != "application/json" {
+		writeValidationError(w, []coleteonline.Error{
+			{Parameter: "Content-Type", Message: "must be application/json"},
+		})
+		return nil, false
+	}
+	var o coleteonline.Order
+	err := json.NewDecoder(r.Body).Decode(&o)
+	if err != nil {
+		writeValidationError(w, []coleteonline.Error{
+			{Parameter: "body", Message: err.Error()},
+		})
+		return nil, false
+	}
+	return &o, true
+}
+
+func writeValidationError(w http.ResponseWriter, errs []coleteonline.Error) {
+	writeJSON(w, http.StatusBadRequest, coleteonline.ResponseError{
+		Message: "Validation failed",
+		Code:    http.StatusBadRequest,
+		Errors:  errs,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+func containsId(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
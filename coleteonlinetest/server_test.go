@@ -0,0 +1,165 @@
+package coleteonlinetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/radulucut/coleteonline"
+)
+
+func Test_Server(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := coleteonline.NewClient(server.Config())
+
+	t.Run("CreateOrder", func(t *testing.T) {
+		order := newOrder()
+		res, err := client.CreateOrder(&order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(server.Orders(), []coleteonline.OrderResponse{*res}); diff != "" {
+			t.Errorf("Orders mismatch (-want +got):\n%s", diff)
+		}
+		stored, ok := server.Order(res.UniqueId)
+		if !ok {
+			t.Fatal("order not stored")
+		}
+		if diff := cmp.Diff(*stored, order); diff != "" {
+			t.Errorf("Order mismatch (-want +got):\n%s", diff)
+		}
+
+		err = server.AdvanceStatus(res.AWB, coleteonline.StatusHistory{
+			Code: coleteonline.StatusCodeDelivered,
+			StatusTextParts: coleteonline.StatusTextParts{
+				Ro: coleteonline.StatusTextPart{Name: "Livrat"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		status, err := client.OrderStatus(&res.UniqueId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(len(status.History), 2); diff != "" {
+			t.Fatalf("History mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(status.History[1].Code, coleteonline.StatusCodeDelivered); diff != "" {
+			t.Errorf("History mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Addresses", func(t *testing.T) {
+		created, err := client.CreateAddress(&coleteonline.OrderAddress{
+			Contact: coleteonline.Contact{Name: "Warehouse"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		created.Contact.Name = "Main warehouse"
+		_, err = client.UpdateAddress(created.AddressId, created)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := client.GetAddress(created.AddressId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(res, created); diff != "" {
+			t.Errorf("Address mismatch (-want +got):\n%s", diff)
+		}
+		err = client.DeleteAddress(created.AddressId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(len(server.Addresses()), 0); diff != "" {
+			t.Errorf("Addresses mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("AddressPageSize", func(t *testing.T) {
+		server.SetAddresses([]coleteonline.OrderAddress{{AddressId: 1}}, 0)
+		defer server.SetAddresses(nil, 0)
+		res, err := client.AddressList(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(len(res.Data), 1); diff != "" {
+			t.Errorf("Addresses mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("ValidationError", func(t *testing.T) {
+		order := newOrder()
+		order.Packages.List = nil
+		_, err := client.OrderPrice(&order)
+		rErr, ok := err.(*coleteonline.ResponseError)
+		if !ok {
+			t.Fatalf("expected *ResponseError, got %v", err)
+		}
+		if diff := cmp.Diff(rErr.Errors, coleteonline.Errors{
+			{Parameter: "packages.list", Message: "at least one package is required"},
+		}); diff != "" {
+			t.Errorf("Errors mismatch (-want +got):\n%s", diff)
+		}
+		if !errors.Is(err, coleteonline.ErrValidation) {
+			t.Errorf("Expected ErrValidation, got %v", err)
+		}
+		if !rErr.Errors.Has("packages") {
+			t.Error("Expected an error for packages")
+		}
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		server.Fail("/user/balance", Failure{Status: 418, Times: 1})
+		_, err := client.UserBalance()
+		if diff := cmp.Diff(err.Error(), `418: "unexpected response status"`); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+		_, err = client.UserBalance()
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("RevokeTokens", func(t *testing.T) {
+		server.RevokeTokens()
+		before := server.RequestCount("/token")
+		_, err := client.ServiceList()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(server.RequestCount("/token"), before+1); diff != "" {
+			t.Errorf("Token requests mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func newOrder() coleteonline.Order {
+	return coleteonline.Order{
+		Sender: coleteonline.Sender{
+			AddressId: 1,
+		},
+		Recipient: coleteonline.Recipient{
+			AddressId: 2,
+		},
+		Packages: coleteonline.Packages{
+			Type:    coleteonline.PackageTypePackage,
+			Content: "Content",
+			List: []coleteonline.Package{
+				{
+					Weight: 1,
+					Width:  1,
+					Height: 1,
+					Length: 1,
+				},
+			},
+		},
+		Service: coleteonline.OrderService{
+			SelectionType: coleteonline.ServiceTypeDirectId,
+			ServiceIds:    []int64{1},
+		},
+	}
+}
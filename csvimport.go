@@ -0,0 +1,482 @@
+package coleteonline
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CSVImporter builds orders from CSV rows, one order with a single package per row.
+//
+// Columns are matched to fields by name, using the JSON parameter paths reported in
+// validation errors, e.g. "recipient.contact.name", "recipient.address.postalCode",
+// "packages.weight" or "service.serviceIds". Extra options use "extraOptions." followed by
+// the option name, e.g. "extraOptions.cashRepayment" or "extraOptions.accountRepayment.iban".
+// See CSVFields for the full list.
+type CSVImporter struct {
+	// Maps CSV headers to field names, e.g. "Greutate (kg)": "packages.weight". Headers are matched
+	// case insensitively. Headers that are field names do not need a mapping, other columns are ignored.
+	Mapping map[string]string
+	// Every row starts as a copy of this order, e.g. to set the sender or the service.
+	// Empty cells keep the default value.
+	Defaults Order
+	// Defaults to ','.
+	Comma rune
+}
+
+// The orders built from the valid rows, ready for CreateOrders or OrderPrice.
+type CSVImport struct {
+	Orders []Order
+	// The line of each order in Orders.
+	Lines  []int
+	Errors []CSVRowError
+}
+
+// Also used for rows that are not valid CSV, e.g. with a misplaced quote, in which case the
+// error has no parameter.
+type CSVRowError struct {
+	// The line the row starts on, counting the header as line 1.
+	Line   int
+	Errors Errors
+}
+
+func (e *CSVRowError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		parts[i] = err.Message
+		if err.Parameter != "" {
+			parts[i] = err.Parameter + ": " + err.Message
+		}
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, strings.Join(parts, "; "))
+}
+
+type csvField func(row *csvRow, value string) error
+
+type csvRow struct {
+	order            Order
+	pkg              Package
+	hasPkg           bool
+	accountRepayment *AccountRepaymentOption
+	scheduledPickup  *ScheduledPickupOption
+}
+
+// Import reads the header and all the rows. Invalid rows, including rows that are not valid CSV,
+// are reported in Errors and left out of Orders. The returned error is only set when the header
+// or the reader itself cannot be read.
+func (i *CSVImporter) Import(r io.Reader) (*CSVImport, error) {
+	reader := csv.NewReader(r)
+	if i.Comma != 0 {
+		reader.Comma = i.Comma
+	}
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &CSVImport{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns, err := i.columns(header)
+	if err != nil {
+		return nil, err
+	}
+	res := &CSVImport{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			res.Errors = append(res.Errors, CSVRowError{
+				Line:   parseErr.StartLine,
+				Errors: Errors{{Message: parseErr.Err.Error()}},
+			})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Quoted cells may span several lines
+		line, _ := reader.FieldPos(0)
+		if isEmptyCSVRecord(record) {
+			continue
+		}
+		order, errs := i.order(columns, record)
+		if len(errs) != 0 {
+			res.Errors = append(res.Errors, CSVRowError{Line: line, Errors: errs})
+			continue
+		}
+		res.Orders = append(res.Orders, order)
+		res.Lines = append(res.Lines, line)
+	}
+	return res, nil
+}
+
+// The field name for each column, empty for ignored columns.
+func (i *CSVImporter) columns(header []string) ([]string, error) {
+	mapping := make(map[string]string, len(i.Mapping))
+	for h, name := range i.Mapping {
+		if _, ok := csvFields[name]; !ok {
+			return nil, fmt.Errorf("unknown field %q mapped from header %q", name, h)
+		}
+		mapping[strings.ToLower(strings.TrimSpace(h))] = name
+	}
+	fieldNames := make(map[string]string, len(csvFields))
+	for name := range csvFields {
+		fieldNames[strings.ToLower(name)] = name
+	}
+	res := make([]string, len(header))
+	for j, h := range header {
+		key := strings.ToLower(strings.TrimSpace(h))
+		if name, ok := mapping[key]; ok {
+			res[j] = name
+		} else if name, ok := fieldNames[key]; ok {
+			res[j] = name
+		}
+	}
+	return res, nil
+}
+
+func (i *CSVImporter) order(columns []string, record []string) (Order, Errors) {
+	row := &csvRow{order: cloneOrder(&i.Defaults)}
+	var errs Errors
+	for j, value := range record {
+		if j >= len(columns) || columns[j] == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		err := csvFields[columns[j]](row, value)
+		if err != nil {
+			errs = append(errs, Error{Parameter: columns[j], Message: err.Error()})
+		}
+	}
+	if row.hasPkg {
+		row.order.Packages.List = append(row.order.Packages.List, row.pkg)
+	}
+	if row.accountRepayment != nil {
+		row.order.AddExtraOption(*row.accountRepayment)
+	}
+	if row.scheduledPickup != nil {
+		row.order.AddExtraOption(*row.scheduledPickup)
+	}
+	if len(errs) != 0 {
+		return Order{}, errs
+	}
+	errs = row.order.Validate()
+	if len(errs) != 0 {
+		return Order{}, errs
+	}
+	return row.order, nil
+}
+
+// CSVFields returns the field names that can be used as headers or in CSVImporter.Mapping.
+func CSVFields() []string {
+	res := make([]string, 0, len(csvFields))
+	for name := range csvFields {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+var csvFields = func() map[string]csvField {
+	fields := map[string]csvField{
+		"packages.type": func(row *csvRow, v string) error {
+			switch strings.ToLower(v) {
+			case "1", "envelope":
+				row.order.Packages.Type = PackageTypeEnvelope
+			case "2", "package":
+				row.order.Packages.Type = PackageTypePackage
+			default:
+				return fmt.Errorf("unknown package type %q", v)
+			}
+			return nil
+		},
+		"packages.content": func(row *csvRow, v string) error {
+			row.order.Packages.Content = v
+			return nil
+		},
+		"service.selectionType": func(row *csvRow, v string) error {
+			row.order.Service.SelectionType = ServiceType(v)
+			return nil
+		},
+		"service.serviceIds": func(row *csvRow, v string) error {
+			row.order.Service.ServiceIds = nil
+			for _, s := range splitCSVList(v) {
+				id, err := strconv.ParseInt(s, 10, 64)
+				if err != nil {
+					return fmt.Errorf("%q is not a valid service id", s)
+				}
+				row.order.Service.ServiceIds = append(row.order.Service.ServiceIds, id)
+			}
+			return nil
+		},
+		"service.grades": func(row *csvRow, v string) error {
+			row.order.Service.Grades = nil
+			for _, s := range splitCSVList(v) {
+				row.order.Service.Grades = append(row.order.Service.Grades, ServiceGrade(s))
+			}
+			return nil
+		},
+		"extraOptions.statusChange":     csvFlagOption(StatusChangeOption{}),
+		"extraOptions.openAtDelivery":   csvFlagOption(OpenAtDeliveryOption{}),
+		"extraOptions.saturdayDelivery": csvFlagOption(SaturdayDeliveryOption{}),
+		"extraOptions.insurance": csvAmountOption(func(amount Money) ExtraOption {
+			return InsuranceOption{Amount: amount}
+		}),
+		"extraOptions.cashRepayment": csvAmountOption(func(amount Money) ExtraOption {
+			return CashRepaymentOption{Amount: amount}
+		}),
+		"extraOptions.declaredValue": csvAmountOption(func(amount Money) ExtraOption {
+			return DeclaredValueOption{Amount: amount}
+		}),
+		"extraOptions.accountRepayment.amount": func(row *csvRow, v string) error {
+			amount, err := parseCSVMoney(v)
+			if err != nil {
+				return err
+			}
+			row.accountRepaymentOption().Amount = amount
+			return nil
+		},
+		"extraOptions.accountRepayment.iban": func(row *csvRow, v string) error {
+			row.accountRepaymentOption().IBAN = v
+			return nil
+		},
+		"extraOptions.accountRepayment.accountHolder": func(row *csvRow, v string) error {
+			row.accountRepaymentOption().AccountHolder = v
+			return nil
+		},
+		"extraOptions.scheduledPickup.date": func(row *csvRow, v string) error {
+			row.scheduledPickupOption().Date = v
+			return nil
+		},
+		"extraOptions.scheduledPickup.startTime": func(row *csvRow, v string) error {
+			row.scheduledPickupOption().StartTime = v
+			return nil
+		},
+		"extraOptions.scheduledPickup.endTime": func(row *csvRow, v string) error {
+			row.scheduledPickupOption().EndTime = v
+			return nil
+		},
+		"extraOptions.clientReference": func(row *csvRow, v string) error {
+			row.order.AddExtraOption(ClientReferenceOption{Reference: v})
+			return nil
+		},
+		"extraOptions.baseCurrency": func(row *csvRow, v string) error {
+			row.order.AddExtraOption(BaseCurrencyOption{Currency: v})
+			return nil
+		},
+	}
+	packageFields := map[string]func(p *Package) *float64{
+		"weight": func(p *Package) *float64 { return &p.Weight },
+		"width":  func(p *Package) *float64 { return &p.Width },
+		"height": func(p *Package) *float64 { return &p.Height },
+		"length": func(p *Package) *float64 { return &p.Length },
+	}
+	for name, field := range packageFields {
+		field := field
+		fields["packages."+name] = func(row *csvRow, v string) error {
+			n, err := parseCSVNumber(v)
+			if err != nil {
+				return err
+			}
+			*field(&row.pkg) = n
+			row.hasPkg = true
+			return nil
+		}
+	}
+	addCSVPartyFields(fields, "sender", func(o *Order) (*int64, **Contact, **Address, *ValidationStrategyType) {
+		return &o.Sender.AddressId, &o.Sender.Contact, &o.Sender.Address, &o.Sender.ValidationStrategy
+	})
+	addCSVPartyFields(fields, "recipient", func(o *Order) (*int64, **Contact, **Address, *ValidationStrategyType) {
+		return &o.Recipient.AddressId, &o.Recipient.Contact, &o.Recipient.Address, &o.Recipient.ValidationStrategy
+	})
+	return fields
+}()
+
+func addCSVPartyFields(
+	fields map[string]csvField,
+	prefix string,
+	party func(o *Order) (*int64, **Contact, **Address, *ValidationStrategyType),
+) {
+	fields[prefix+".addressId"] = func(row *csvRow, v string) error {
+		addressId, _, _, _ := party(&row.order)
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		*addressId = id
+		return nil
+	}
+	fields[prefix+".validationStrategy"] = func(row *csvRow, v string) error {
+		_, _, _, strategy := party(&row.order)
+		*strategy = ValidationStrategyType(v)
+		return nil
+	}
+	contactFields := map[string]func(c *Contact) *string{
+		"name":    func(c *Contact) *string { return &c.Name },
+		"phone":   func(c *Contact) *string { return &c.Phone },
+		"phone2":  func(c *Contact) *string { return &c.Phone2 },
+		"company": func(c *Contact) *string { return &c.Company },
+		"email":   func(c *Contact) *string { return &c.Email },
+	}
+	for name, field := range contactFields {
+		field := field
+		fields[prefix+".contact."+name] = func(row *csvRow, v string) error {
+			_, contact, _, _ := party(&row.order)
+			if *contact == nil {
+				*contact = &Contact{}
+			}
+			*field(*contact) = v
+			return nil
+		}
+	}
+	addressFields := map[string]func(a *Address) *string{
+		"countryCode":    func(a *Address) *string { return &a.CountryCode },
+		"postalCode":     func(a *Address) *string { return &a.PostalCode },
+		"city":           func(a *Address) *string { return &a.City },
+		"county":         func(a *Address) *string { return &a.County },
+		"countyCode":     func(a *Address) *string { return &a.CountyCode },
+		"street":         func(a *Address) *string { return &a.Street },
+		"number":         func(a *Address) *string { return &a.Number },
+		"building":       func(a *Address) *string { return &a.Building },
+		"entrance":       func(a *Address) *string { return &a.Entrance },
+		"intercom":       func(a *Address) *string { return &a.Intercom },
+		"floor":          func(a *Address) *string { return &a.Floor },
+		"apartment":      func(a *Address) *string { return &a.Apartment },
+		"landmark":       func(a *Address) *string { return &a.Landmark },
+		"additionalInfo": func(a *Address) *string { return &a.AdditionalInfo },
+	}
+	for name, field := range addressFields {
+		field := field
+		fields[prefix+".address."+name] = func(row *csvRow, v string) error {
+			_, _, address, _ := party(&row.order)
+			if *address == nil {
+				*address = &Address{}
+			}
+			*field(*address) = v
+			return nil
+		}
+	}
+}
+
+// Adds the option when the cell is a true value such as "1", "true" or "yes".
+func csvFlagOption(option ExtraOption) csvField {
+	return func(row *csvRow, v string) error {
+		switch strings.ToLower(v) {
+		case "1", "true", "yes", "y":
+			row.order.AddExtraOption(option)
+		case "0", "false", "no", "n":
+		default:
+			return fmt.Errorf("%q is not a valid boolean", v)
+		}
+		return nil
+	}
+}
+
+func csvAmountOption(option func(amount Money) ExtraOption) csvField {
+	return func(row *csvRow, v string) error {
+		amount, err := parseCSVMoney(v)
+		if err != nil {
+			return err
+		}
+		row.order.AddExtraOption(option(amount))
+		return nil
+	}
+}
+
+func (r *csvRow) accountRepaymentOption() *AccountRepaymentOption {
+	if r.accountRepayment == nil {
+		r.accountRepayment = &AccountRepaymentOption{}
+	}
+	return r.accountRepayment
+}
+
+func (r *csvRow) scheduledPickupOption() *ScheduledPickupOption {
+	if r.scheduledPickup == nil {
+		r.scheduledPickup = &ScheduledPickupOption{}
+	}
+	return r.scheduledPickup
+}
+
+// Accepts a decimal comma, as exported by spreadsheets in Romanian locales.
+func parseCSVNumber(v string) (float64, error) {
+	if !strings.Contains(v, ".") {
+		v = strings.Replace(v, ",", ".", 1)
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	// ParseFloat also accepts "NaN" and "Inf", which pass validation but cannot be sent as JSON
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, errors.New("must be a number")
+	}
+	return n, nil
+}
+
+// Amounts are in DefaultCurrency and accept a decimal comma like parseCSVNumber.
+func parseCSVMoney(v string) (Money, error) {
+	if !strings.Contains(v, ".") {
+		v = strings.Replace(v, ",", ".", 1)
+	}
+	m, err := ParseMoney(v, DefaultCurrency)
+	if err != nil {
+		return Money{}, errors.New("must be an amount")
+	}
+	return m, nil
+}
+
+// Lists are separated by semicolons, since commas usually separate the columns.
+func splitCSVList(v string) []string {
+	var res []string
+	for _, s := range strings.Split(v, ";") {
+		s = strings.TrimSpace(s)
+		if s != "" {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func isEmptyCSVRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Copies the pointers and slices so rows do not share them.
+func cloneOrder(o *Order) Order {
+	res := *o
+	for _, party := range []struct {
+		contact **Contact
+		address **Address
+	}{
+		{&res.Sender.Contact, &res.Sender.Address},
+		{&res.Recipient.Contact, &res.Recipient.Address},
+	} {
+		if *party.contact != nil {
+			c := **party.contact
+			*party.contact = &c
+		}
+		if *party.address != nil {
+			a := **party.address
+			*party.address = &a
+		}
+	}
+	res.Packages.List = append([]Package(nil), o.Packages.List...)
+	res.Service.ServiceIds = append([]int64(nil), o.Service.ServiceIds...)
+	res.Service.Grades = append([]ServiceGrade(nil), o.Service.Grades...)
+	res.ExtraOptions = append([]interface{}(nil), o.ExtraOptions...)
+	return res
+}
@@ -0,0 +1,160 @@
+package coleteonline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_CSVImporter(t *testing.T) {
+	importer := &CSVImporter{
+		Mapping: map[string]string{
+			"Nume":     "recipient.contact.name",
+			"Telefon":  "recipient.contact.phone",
+			"Oras":     "recipient.address.city",
+			"Judet":    "recipient.address.county",
+			"Strada":   "recipient.address.street",
+			"Greutate": "packages.weight",
+			"Ramburs":  "extraOptions.cashRepayment",
+		},
+		Defaults: Order{
+			Sender: Sender{AddressId: 1},
+			Recipient: Recipient{
+				Address: &Address{CountryCode: "RO"},
+			},
+			Packages: Packages{
+				Type:    PackageTypeEnvelope,
+				Content: "Documents",
+			},
+			Service: OrderService{SelectionType: ServiceTypeBestPrice},
+		},
+	}
+	input := strings.Join([]string{
+		"Nume,Telefon,Oras,Judet,Strada,Greutate,Ramburs,extraOptions.openAtDelivery,Notes",
+		"Ion Popescu,0700000000,Cluj-Napoca,Cluj,Strada Lunga 1,\"1,5\",120.50,yes,fragile",
+		"Maria Ionescu,,Iasi,Iasi,Strada Mare 2,abc,,no,",
+		",,,,,,,,",
+		"Ana Pop,0711111111,Brasov,Brasov,Strada Noua 3,2,,,",
+	}, "\n")
+	res, err := importer.Import(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &CSVImport{
+		Orders: []Order{
+			{
+				Sender: Sender{AddressId: 1},
+				Recipient: Recipient{
+					Contact: &Contact{Name: "Ion Popescu", Phone: "0700000000"},
+					Address: &Address{CountryCode: "RO", City: "Cluj-Napoca", County: "Cluj", Street: "Strada Lunga 1"},
+				},
+				Packages: Packages{
+					Type:    PackageTypeEnvelope,
+					Content: "Documents",
+					List:    []Package{{Weight: 1.5}},
+				},
+				Service:      OrderService{SelectionType: ServiceTypeBestPrice},
+				ExtraOptions: []interface{}{CashRepaymentOption{Amount: NewMoney(12050, DefaultCurrency)}, OpenAtDeliveryOption{}},
+			},
+			{
+				Sender: Sender{AddressId: 1},
+				Recipient: Recipient{
+					Contact: &Contact{Name: "Ana Pop", Phone: "0711111111"},
+					Address: &Address{CountryCode: "RO", City: "Brasov", County: "Brasov", Street: "Strada Noua 3"},
+				},
+				Packages: Packages{
+					Type:    PackageTypeEnvelope,
+					Content: "Documents",
+					List:    []Package{{Weight: 2}},
+				},
+				Service: OrderService{SelectionType: ServiceTypeBestPrice},
+			},
+		},
+		Lines: []int{2, 5},
+		Errors: []CSVRowError{
+			{
+				Line: 3,
+				Errors: Errors{
+					{Parameter: "packages.weight", Message: "must be a number"},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, res); diff != "" {
+		t.Errorf("Import mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(importer.Defaults.Recipient.Address, &Address{CountryCode: "RO"}); diff != "" {
+		t.Errorf("Defaults mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_CSVImporter_UnknownField(t *testing.T) {
+	importer := &CSVImporter{
+		Mapping: map[string]string{"Weight": "packages.mass"},
+	}
+	_, err := importer.Import(strings.NewReader("Weight\n1\n"))
+	if err == nil || err.Error() != `unknown field "packages.mass" mapped from header "Weight"` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func Test_CSVImporter_Lines(t *testing.T) {
+	importer := &CSVImporter{
+		Defaults: Order{
+			Sender: Sender{AddressId: 1},
+			Recipient: Recipient{
+				Contact: &Contact{Phone: "0700000000"},
+				Address: &Address{CountryCode: "RO", City: "Cluj-Napoca", County: "Cluj"},
+			},
+			Packages: Packages{
+				Type:    PackageTypeEnvelope,
+				Content: "Documents",
+			},
+			Service: OrderService{SelectionType: ServiceTypeBestPrice},
+		},
+	}
+	input := strings.Join([]string{
+		"recipient.contact.name,recipient.address.street,packages.weight",
+		"Ion,\"Strada Lunga 1",
+		"Bloc A\",1",
+		"Maria,Strada \"Mare\" 2,1",
+		"Ana,Strada Noua 3,abc",
+		"Ion,Strada Noua 4,NaN",
+		"Ion,Strada Noua 5,-Inf",
+	}, "\n")
+	res, err := importer.Import(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res.Lines, []int{2}); diff != "" {
+		t.Fatalf("Lines mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(res.Orders[0].Recipient.Address.Street, "Strada Lunga 1\nBloc A"); diff != "" {
+		t.Errorf("Street mismatch (-want +got):\n%s", diff)
+	}
+	expected := []CSVRowError{
+		{
+			Line:   4,
+			Errors: Errors{{Message: `bare " in non-quoted-field`}},
+		},
+		{
+			Line:   5,
+			Errors: Errors{{Parameter: "packages.weight", Message: "must be a number"}},
+		},
+		{
+			Line:   6,
+			Errors: Errors{{Parameter: "packages.weight", Message: "must be a number"}},
+		},
+		{
+			Line:   7,
+			Errors: Errors{{Parameter: "packages.weight", Message: "must be a number"}},
+		},
+	}
+	if diff := cmp.Diff(expected, res.Errors); diff != "" {
+		t.Errorf("Errors mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(res.Errors[0].Error(), `line 4: bare " in non-quoted-field`); diff != "" {
+		t.Errorf("Error mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -1,27 +1,156 @@
 package coleteonline
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Matched with errors.Is against *ResponseError and *AuthResponseError, based on the HTTP status.
+var (
+	// 401 and 403 responses, or rejected client credentials.
+	ErrUnauthorized = errors.New("unauthorized")
+	// 429 responses, returned once the retry policy gave up.
+	ErrRateLimited = errors.New("rate limited")
+	ErrNotFound    = errors.New("not found")
+	// 5xx responses.
+	ErrServer = errors.New("server error")
+	// 400 and 422 responses, the details are in ResponseError.Errors.
+	ErrValidation = errors.New("validation failed")
+)
 
 type Error struct {
 	Parameter string `json:"parameter"`
 	Message   string `json:"message"`
 }
 
+type Errors []Error
+
+// Get returns the first error for exactly this parameter.
+func (e Errors) Get(parameter string) (Error, bool) {
+	for _, err := range e {
+		if err.Parameter == parameter {
+			return err, true
+		}
+	}
+	return Error{}, false
+}
+
+// For returns the errors for the parameter and the parameters nested in it, e.g. "recipient"
+// matches "recipient.contact.name" and "packages.list" matches "packages.list[0].weight".
+func (e Errors) For(parameter string) Errors {
+	var res Errors
+	for _, err := range e {
+		if isParameterOrChild(err.Parameter, parameter) {
+			res = append(res, err)
+		}
+	}
+	return res
+}
+
+func (e Errors) Has(parameter string) bool {
+	for _, err := range e {
+		if isParameterOrChild(err.Parameter, parameter) {
+			return true
+		}
+	}
+	return false
+}
+
+// Messages groups the messages by parameter.
+func (e Errors) Messages() map[string][]string {
+	res := make(map[string][]string, len(e))
+	for _, err := range e {
+		res[err.Parameter] = append(res[err.Parameter], err.Message)
+	}
+	return res
+}
+
+func isParameterOrChild(parameter string, parent string) bool {
+	if !strings.HasPrefix(parameter, parent) {
+		return false
+	}
+	rest := parameter[len(parent):]
+	return rest == "" || rest[0] == '.' || rest[0] == '['
+}
+
 type ResponseError struct {
-	Message string  `json:"message"`
-	Code    int     `json:"code"`
-	Errors  []Error `json:"errors"`
+	Message string `json:"message"`
+	// The code returned by the API, or the HTTP status when the response has no error body.
+	Code   int    `json:"code"`
+	Errors Errors `json:"errors"`
+	// The HTTP status of the response.
+	StatusCode int `json:"-"`
+	// The X-Request-Id header of the response, when set.
+	RequestId string `json:"-"`
+	// The raw response body, up to 1MB.
+	Body []byte `json:"-"`
+	// Set when the body could not be decoded.
+	Err error `json:"-"`
 }
 
 func (e *ResponseError) Error() string {
-	return fmt.Sprintf(`%d: "%s"`, e.Code, e.Message)
+	code := e.Code
+	if code == 0 {
+		code = e.StatusCode
+	}
+	return fmt.Sprintf(`%d: "%s"`, code, e.Message)
+}
+
+func (e *ResponseError) Is(target error) bool {
+	return statusIs(e.StatusCode, target)
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
 }
 
 type AuthResponseError struct {
 	Name        string `json:"error"`
 	Description string `json:"error_description"`
+	// The HTTP status of the response.
+	StatusCode int `json:"-"`
+	// The X-Request-Id header of the response, when set.
+	RequestId string `json:"-"`
+	// The raw response body, up to 1MB.
+	Body []byte `json:"-"`
+	// Set when the body could not be decoded.
+	Err error `json:"-"`
 }
 
 func (e *AuthResponseError) Error() string {
 	return fmt.Sprintf(`%s: "%s"`, e.Name, e.Description)
 }
+
+// Any rejected token request is reported as ErrUnauthorized, unless it was rate limited or
+// failed on the server.
+func (e *AuthResponseError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode >= 400 && e.StatusCode < 500 && e.StatusCode != http.StatusTooManyRequests
+	case ErrRateLimited, ErrServer:
+		return statusIs(e.StatusCode, target)
+	}
+	return false
+}
+
+func (e *AuthResponseError) Unwrap() error {
+	return e.Err
+}
+
+func statusIs(status int, target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return status == http.StatusUnauthorized || status == http.StatusForbidden
+	case ErrRateLimited:
+		return status == http.StatusTooManyRequests
+	case ErrNotFound:
+		return status == http.StatusNotFound
+	case ErrServer:
+		return status >= 500
+	case ErrValidation:
+		return status == http.StatusBadRequest || status == http.StatusUnprocessableEntity
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package coleteonline
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Errors(t *testing.T) {
+	errs := Errors{
+		{Parameter: "recipient.contact.name", Message: "is required"},
+		{Parameter: "recipient.contactless", Message: "is invalid"},
+		{Parameter: "packages.list[0].weight", Message: "must be greater than 0"},
+		{Parameter: "packages.list[0].weight", Message: "is too large"},
+	}
+	err, ok := errs.Get("packages.list[0].weight")
+	if !ok {
+		t.Fatal("Expected an error for packages.list[0].weight")
+	}
+	if diff := cmp.Diff(err.Message, "must be greater than 0"); diff != "" {
+		t.Errorf("Message mismatch (-want +got):\n%s", diff)
+	}
+	_, ok = errs.Get("packages")
+	if ok {
+		t.Error("Get should only match the exact parameter")
+	}
+	if diff := cmp.Diff(errs.For("recipient.contact"), Errors{errs[0]}); diff != "" {
+		t.Errorf("For mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(errs.For("packages.list"), Errors{errs[2], errs[3]}); diff != "" {
+		t.Errorf("For mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]bool{errs.Has("recipient"), errs.Has("sender")}, []bool{true, false}); diff != "" {
+		t.Errorf("Has mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(errs.Messages()["packages.list[0].weight"], []string{"must be greater than 0", "is too large"}); diff != "" {
+		t.Errorf("Messages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_NewResponseError(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Request-Id", "req_1")
+	body := []byte(`{"message":"Validation failed","code":400,"errors":[{"parameter":"sender","message":"is required"}]}`)
+	err := newResponseError(&http.Response{StatusCode: 400, Header: header}, body)
+	expected := &ResponseError{
+		Message:    "Validation failed",
+		Code:       400,
+		Errors:     Errors{{Parameter: "sender", Message: "is required"}},
+		StatusCode: 400,
+		RequestId:  "req_1",
+		Body:       body,
+	}
+	if diff := cmp.Diff(expected, err); diff != "" {
+		t.Errorf("Error mismatch (-want +got):\n%s", diff)
+	}
+	if !errors.Is(err, ErrValidation) || errors.Is(err, ErrNotFound) {
+		t.Errorf("Unexpected classification for %v", err)
+	}
+
+	err = newResponseError(&http.Response{StatusCode: 502, Header: http.Header{}}, []byte("<html>Bad Gateway</html>"))
+	if diff := cmp.Diff(err.Error(), `502: "unexpected response status"`); diff != "" {
+		t.Errorf("Error mismatch (-want +got):\n%s", diff)
+	}
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("Expected ErrServer, got %v", err)
+	}
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected the decode error to be wrapped, got %v", err.Err)
+	}
+
+	err = newResponseError(&http.Response{StatusCode: 404, Header: http.Header{}}, nil)
+	if !errors.Is(err, ErrNotFound) || err.Err != nil {
+		t.Errorf("Expected ErrNotFound without a decode error, got %v", err)
+	}
+}
+
+func Test_AuthResponseError_Is(t *testing.T) {
+	for _, c := range []struct {
+		status   int
+		expected error
+	}{
+		{400, ErrUnauthorized},
+		{401, ErrUnauthorized},
+		{429, ErrRateLimited},
+		{500, ErrServer},
+	} {
+		err := &AuthResponseError{Name: "error", StatusCode: c.status}
+		if !errors.Is(err, c.expected) {
+			t.Errorf("%d: expected %v", c.status, c.expected)
+		}
+	}
+	if errors.Is(&AuthResponseError{StatusCode: 429}, ErrUnauthorized) {
+		t.Error("429 should not be ErrUnauthorized")
+	}
+}
@@ -0,0 +1,209 @@
+package coleteonline
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Implemented by the typed extra options, which marshal to the shape expected in Order.ExtraOptions.
+type ExtraOption interface {
+	OptionId() ExtraOptionId
+}
+
+type StatusChangeOption struct{}
+
+type OpenAtDeliveryOption struct{}
+
+type SaturdayDeliveryOption struct{}
+
+type InsuranceOption struct {
+	Amount Money `json:"amount"`
+}
+
+type AccountRepaymentOption struct {
+	Amount        Money  `json:"amount"`
+	IBAN          string `json:"iban"`
+	AccountHolder string `json:"accountHolder,omitempty"`
+}
+
+type CashRepaymentOption struct {
+	Amount Money `json:"amount"`
+}
+
+type DeclaredValueOption struct {
+	Amount Money `json:"amount"`
+}
+
+// Date is formatted as YYYY-MM-DD and the interval bounds as HH:MM.
+type ScheduledPickupOption struct {
+	Date      string `json:"date"`
+	StartTime string `json:"startTime,omitempty"`
+	EndTime   string `json:"endTime,omitempty"`
+}
+
+type ClientReferenceOption struct {
+	Reference string `json:"reference"`
+}
+
+type BaseCurrencyOption struct {
+	Currency string `json:"currency"`
+}
+
+func (StatusChangeOption) OptionId() ExtraOptionId     { return ExtraOptionIdStatusChange }
+func (OpenAtDeliveryOption) OptionId() ExtraOptionId   { return ExtraOptionIdOpenAtDelivery }
+func (SaturdayDeliveryOption) OptionId() ExtraOptionId { return ExtraOptionIdSaturdayDelivery }
+func (InsuranceOption) OptionId() ExtraOptionId        { return ExtraOptionIdInsurance }
+func (AccountRepaymentOption) OptionId() ExtraOptionId { return ExtraOptionIdAccountRepayment }
+func (CashRepaymentOption) OptionId() ExtraOptionId    { return ExtraOptionIdCashRepayment }
+func (DeclaredValueOption) OptionId() ExtraOptionId    { return ExtraOptionIdDeclaredValue }
+func (ScheduledPickupOption) OptionId() ExtraOptionId  { return ExtraOptionIdScheduledPickup }
+func (ClientReferenceOption) OptionId() ExtraOptionId  { return ExtraOptionIdClientReference }
+func (BaseCurrencyOption) OptionId() ExtraOptionId     { return ExtraOptionIdBaseCurrency }
+
+func (o StatusChangeOption) MarshalJSON() ([]byte, error) {
+	return marshalExtraOption(o.OptionId(), struct{}{})
+}
+
+func (o OpenAtDeliveryOption) MarshalJSON() ([]byte, error) {
+	return marshalExtraOption(o.OptionId(), struct{}{})
+}
+
+func (o SaturdayDeliveryOption) MarshalJSON() ([]byte, error) {
+	return marshalExtraOption(o.OptionId(), struct{}{})
+}
+
+func (o InsuranceOption) MarshalJSON() ([]byte, error) {
+	type option InsuranceOption
+	return marshalExtraOption(o.OptionId(), option(o))
+}
+
+func (o AccountRepaymentOption) MarshalJSON() ([]byte, error) {
+	type option AccountRepaymentOption
+	return marshalExtraOption(o.OptionId(), option(o))
+}
+
+func (o CashRepaymentOption) MarshalJSON() ([]byte, error) {
+	type option CashRepaymentOption
+	return marshalExtraOption(o.OptionId(), option(o))
+}
+
+func (o DeclaredValueOption) MarshalJSON() ([]byte, error) {
+	type option DeclaredValueOption
+	return marshalExtraOption(o.OptionId(), option(o))
+}
+
+func (o ScheduledPickupOption) MarshalJSON() ([]byte, error) {
+	type option ScheduledPickupOption
+	return marshalExtraOption(o.OptionId(), option(o))
+}
+
+func (o ClientReferenceOption) MarshalJSON() ([]byte, error) {
+	type option ClientReferenceOption
+	return marshalExtraOption(o.OptionId(), option(o))
+}
+
+func (o BaseCurrencyOption) MarshalJSON() ([]byte, error) {
+	type option BaseCurrencyOption
+	return marshalExtraOption(o.OptionId(), option(o))
+}
+
+// Prepends the id to the JSON object of the option fields.
+func marshalExtraOption(id ExtraOptionId, fields interface{}) ([]byte, error) {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	res := []byte(fmt.Sprintf(`{"id":%d`, id))
+	if len(b) > 2 {
+		res = append(res, ',')
+		res = append(res, b[1:]...)
+	} else {
+		res = append(res, '}')
+	}
+	return res, nil
+}
+
+func UnmarshalExtraOption(b []byte) (ExtraOption, error) {
+	var head struct {
+		Id ExtraOptionId `json:"id"`
+	}
+	err := json.Unmarshal(b, &head)
+	if err != nil {
+		return nil, err
+	}
+	var option ExtraOption
+	switch head.Id {
+	case ExtraOptionIdStatusChange:
+		return StatusChangeOption{}, nil
+	case ExtraOptionIdOpenAtDelivery:
+		return OpenAtDeliveryOption{}, nil
+	case ExtraOptionIdSaturdayDelivery:
+		return SaturdayDeliveryOption{}, nil
+	case ExtraOptionIdInsurance:
+		var o InsuranceOption
+		err = json.Unmarshal(b, &o)
+		option = o
+	case ExtraOptionIdAccountRepayment:
+		var o AccountRepaymentOption
+		err = json.Unmarshal(b, &o)
+		option = o
+	case ExtraOptionIdCashRepayment:
+		var o CashRepaymentOption
+		err = json.Unmarshal(b, &o)
+		option = o
+	case ExtraOptionIdDeclaredValue:
+		var o DeclaredValueOption
+		err = json.Unmarshal(b, &o)
+		option = o
+	case ExtraOptionIdScheduledPickup:
+		var o ScheduledPickupOption
+		err = json.Unmarshal(b, &o)
+		option = o
+	case ExtraOptionIdClientReference:
+		var o ClientReferenceOption
+		err = json.Unmarshal(b, &o)
+		option = o
+	case ExtraOptionIdBaseCurrency:
+		var o BaseCurrencyOption
+		err = json.Unmarshal(b, &o)
+		option = o
+	default:
+		return nil, fmt.Errorf("unknown extra option id: %d", head.Id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return option, nil
+}
+
+func (o *Order) AddExtraOption(options ...ExtraOption) *Order {
+	for _, option := range options {
+		o.ExtraOptions = append(o.ExtraOptions, option)
+	}
+	return o
+}
+
+// Converts ExtraOptions to their typed form, including untyped values such as maps
+// or options decoded from JSON.
+func (o *Order) TypedExtraOptions() ([]ExtraOption, error) {
+	res := make([]ExtraOption, 0, len(o.ExtraOptions))
+	for _, v := range o.ExtraOptions {
+		option, err := toExtraOption(v)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, option)
+	}
+	return res, nil
+}
+
+func toExtraOption(v interface{}) (ExtraOption, error) {
+	if option, ok := v.(ExtraOption); ok {
+		return option, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalExtraOption(b)
+}
@@ -0,0 +1,74 @@
+package coleteonline
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ExtraOptions(t *testing.T) {
+	t.Run("Marshal", func(t *testing.T) {
+		order := Order{}
+		order.AddExtraOption(
+			OpenAtDeliveryOption{},
+			InsuranceOption{Amount: NewMoney(100000, DefaultCurrency)},
+			AccountRepaymentOption{Amount: NewMoney(15050, DefaultCurrency), IBAN: "RO49AAAA1B31007593840000", AccountHolder: "Holder"},
+			ScheduledPickupOption{Date: "2023-01-02", StartTime: "10:00", EndTime: "14:00"},
+			ClientReferenceOption{Reference: "ref_1234"},
+		)
+		b, err := json.Marshal(order.ExtraOptions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := `[{"id":2},` +
+			`{"id":4,"amount":1000.00},` +
+			`{"id":5,"amount":150.50,"iban":"RO49AAAA1B31007593840000","accountHolder":"Holder"},` +
+			`{"id":8,"date":"2023-01-02","startTime":"10:00","endTime":"14:00"},` +
+			`{"id":9,"reference":"ref_1234"}]`
+		if diff := cmp.Diff(string(b), expected); diff != "" {
+			t.Errorf("Extra options mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		options := []ExtraOption{
+			StatusChangeOption{},
+			OpenAtDeliveryOption{},
+			SaturdayDeliveryOption{},
+			InsuranceOption{Amount: NewMoney(100000, DefaultCurrency)},
+			AccountRepaymentOption{Amount: NewMoney(15050, DefaultCurrency), IBAN: "RO49AAAA1B31007593840000"},
+			CashRepaymentOption{Amount: NewMoney(9999, DefaultCurrency)},
+			DeclaredValueOption{Amount: NewMoney(50000, DefaultCurrency)},
+			ScheduledPickupOption{Date: "2023-01-02", StartTime: "10:00", EndTime: "14:00"},
+			ClientReferenceOption{Reference: "ref_1234"},
+			BaseCurrencyOption{Currency: "EUR"},
+		}
+		order := newOrder()
+		order.ExtraOptions = nil
+		order.AddExtraOption(options...)
+		b, err := json.Marshal(order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Order
+		err = json.Unmarshal(b, &decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := decoded.TypedExtraOptions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(res, options); diff != "" {
+			t.Errorf("Extra options mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("UnknownId", func(t *testing.T) {
+		_, err := UnmarshalExtraOption([]byte(`{"id":42}`))
+		if diff := cmp.Diff(err.Error(), "unknown extra option id: 42"); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
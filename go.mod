@@ -1,5 +1,5 @@
 module github.com/radulucut/coleteonline
 
-go 1.16
+go 1.17
 
 require github.com/google/go-cmp v0.6.0
@@ -0,0 +1,319 @@
+package coleteonline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var ErrOutcomeUnknown = errors.New("order outcome unknown")
+
+// Returned by IdempotentOrders.Create when an earlier request with the key was sent but no
+// response was recorded, so the order may or may not exist. It matches ErrOutcomeUnknown with
+// errors.Is.
+type OutcomeUnknownError struct {
+	Key string
+	// When the earlier request was made.
+	CreatedAt time.Time
+}
+
+func (e *OutcomeUnknownError) Error() string {
+	return fmt.Sprintf("%s: idempotency key %q", ErrOutcomeUnknown, e.Key)
+}
+
+func (e *OutcomeUnknownError) Is(target error) bool {
+	return target == ErrOutcomeUnknown
+}
+
+type IdempotencyRecord struct {
+	// The client reference stamped on the order.
+	Key string `json:"key"`
+	// Nil while the outcome of the request is unknown.
+	Response  *OrderResponse `json:"response,omitempty"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+type IdempotencyStore interface {
+	// Returns nil and no error when the key is unknown.
+	Get(key string) (*IdempotencyRecord, error)
+	Put(record IdempotencyRecord) error
+	// Deleting an unknown key is not an error.
+	Delete(key string) error
+}
+
+// Keeps the records in memory, so they are lost when the process exits.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		records: make(map[string]IdempotencyRecord),
+	}
+}
+
+func (s *MemoryIdempotencyStore) Get(key string) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (s *MemoryIdempotencyStore) Put(record IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.Key] = record
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// Persists the records as a JSON object keyed by Key in the file at Path, rewritten
+// atomically on every Put and Delete.
+type JSONFileIdempotencyStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (s *JSONFileIdempotencyStore) Get(key string) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	record, ok := records[key]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (s *JSONFileIdempotencyStore) Put(record IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	records[record.Key] = record
+	return s.save(records)
+}
+
+func (s *JSONFileIdempotencyStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := records[key]; !ok {
+		return nil
+	}
+	delete(records, key)
+	return s.save(records)
+}
+
+// Must be called with the lock held.
+func (s *JSONFileIdempotencyStore) save(records map[string]IdempotencyRecord) error {
+	b, err := json.MarshalIndent(records, "", "\t")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.Path, b)
+}
+
+// Must be called with the lock held.
+func (s *JSONFileIdempotencyStore) load() (map[string]IdempotencyRecord, error) {
+	res := make(map[string]IdempotencyRecord)
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// IdempotentOrders creates each order at most once per key, so a create that failed can be
+// retried without shipping twice.
+//
+// The key is sent as the client reference of the order and recorded in the store before the
+// request. The API cannot look orders up by client reference, so when the order request was sent
+// but failed without a definite answer, e.g. on a timeout or a 5xx response, the record is kept
+// without a response and any later Create with the key returns an *OutcomeUnknownError. Once the
+// order was checked, e.g. by its client reference in the Colete Online dashboard, call Resolve if
+// it exists or Discard if it does not. Failures before the order request is sent, e.g. while
+// fetching the token or checking the balance, delete the record.
+type IdempotentOrders struct {
+	client   *Client
+	store    IdempotencyStore
+	mu       sync.Mutex
+	inFlight map[string]chan struct{}
+}
+
+func NewIdempotentOrders(client *Client, store IdempotencyStore) *IdempotentOrders {
+	return &IdempotentOrders{
+		client:   client,
+		store:    store,
+		inFlight: make(map[string]chan struct{}),
+	}
+}
+
+// Create returns the order already created for key, or creates it with key as its client reference.
+// The order is not modified.
+func (o *IdempotentOrders) Create(ctx context.Context, key string, order *Order) (*OrderResponse, error) {
+	if key == "" {
+		return nil, errors.New("idempotency key is required")
+	}
+	stamped, err := stampClientReference(order, key)
+	if err != nil {
+		return nil, err
+	}
+	err = o.acquire(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer o.release(key)
+	record, err := o.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil && record.Response != nil {
+		return record.Response, nil
+	}
+	if record != nil {
+		return nil, &OutcomeUnknownError{Key: key, CreatedAt: record.CreatedAt}
+	}
+	record = &IdempotencyRecord{Key: key, CreatedAt: o.client.timeNow()}
+	err = o.store.Put(*record)
+	if err != nil {
+		return nil, err
+	}
+	sent := false
+	res, err := o.client.createOrder(ctx, &stamped, func() { sent = true })
+	if err != nil {
+		if !sent || isOrderRejected(err) {
+			if deleteErr := o.store.Delete(key); deleteErr != nil {
+				return nil, deleteErr
+			}
+		}
+		return nil, err
+	}
+	record.Response = res
+	err = o.store.Put(*record)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Resolve records res as the order created for key, after an *OutcomeUnknownError.
+func (o *IdempotentOrders) Resolve(ctx context.Context, key string, res *OrderResponse) error {
+	err := o.acquire(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer o.release(key)
+	record, err := o.store.Get(key)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		record = &IdempotencyRecord{Key: key, CreatedAt: o.client.timeNow()}
+	}
+	record.Response = res
+	return o.store.Put(*record)
+}
+
+// Discard forgets key, so the next Create with it creates the order. After an
+// *OutcomeUnknownError it must only be called once the order is known not to exist.
+func (o *IdempotentOrders) Discard(ctx context.Context, key string) error {
+	err := o.acquire(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer o.release(key)
+	return o.store.Delete(key)
+}
+
+// Reports whether the API answered the order request without creating the order: a 4xx status,
+// or a token that could not be refreshed after a 401.
+func isOrderRejected(err error) bool {
+	var responseErr *ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.StatusCode >= 400 && responseErr.StatusCode < 500
+	}
+	var authErr *AuthResponseError
+	return errors.As(err, &authErr)
+}
+
+// Waits for any other Create with the same key to finish.
+func (o *IdempotentOrders) acquire(ctx context.Context, key string) error {
+	for {
+		o.mu.Lock()
+		done, ok := o.inFlight[key]
+		if !ok {
+			o.inFlight[key] = make(chan struct{})
+			o.mu.Unlock()
+			return nil
+		}
+		o.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-done:
+		}
+	}
+}
+
+func (o *IdempotentOrders) release(key string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	close(o.inFlight[key])
+	delete(o.inFlight, key)
+}
+
+// Returns a copy of the order with the key as its client reference.
+func stampClientReference(order *Order, key string) (Order, error) {
+	res := cloneOrder(order)
+	options, err := order.TypedExtraOptions()
+	if err != nil {
+		return Order{}, err
+	}
+	for _, option := range options {
+		var reference string
+		switch o := option.(type) {
+		case ClientReferenceOption:
+			reference = o.Reference
+		case *ClientReferenceOption:
+			reference = o.Reference
+		default:
+			continue
+		}
+		if reference != key {
+			return Order{}, fmt.Errorf("order client reference %q does not match the idempotency key %q", reference, key)
+		}
+		return res, nil
+	}
+	res.AddExtraOption(ClientReferenceOption{Reference: key})
+	return res, nil
+}
@@ -0,0 +1,171 @@
+package coleteonline_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/radulucut/coleteonline"
+	"github.com/radulucut/coleteonline/coleteonlinetest"
+)
+
+func Test_IdempotentOrders(t *testing.T) {
+	server := coleteonlinetest.NewServer()
+	defer server.Close()
+	client := coleteonline.NewClient(server.Config())
+	store := &coleteonline.JSONFileIdempotencyStore{Path: filepath.Join(t.TempDir(), "orders.json")}
+	orders := coleteonline.NewIdempotentOrders(client, store)
+	ctx := context.Background()
+	order := newTestOrder()
+
+	t.Run("Create", func(t *testing.T) {
+		var wg sync.WaitGroup
+		results := make([]*coleteonline.OrderResponse, 5)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				res, err := orders.Create(ctx, "ref-1", &order)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				results[i] = res
+			}(i)
+		}
+		wg.Wait()
+		for _, res := range results[1:] {
+			if diff := cmp.Diff(results[0], res); diff != "" {
+				t.Errorf("Response mismatch (-want +got):\n%s", diff)
+			}
+		}
+		if diff := cmp.Diff(len(server.Orders()), 1); diff != "" {
+			t.Errorf("Orders mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(order.ExtraOptions, []interface{}(nil)); diff != "" {
+			t.Errorf("Order modified (-want +got):\n%s", diff)
+		}
+		stored, _ := server.Order(results[0].UniqueId)
+		options, err := stored.TypedExtraOptions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(options, []coleteonline.ExtraOption{coleteonline.ClientReferenceOption{Reference: "ref-1"}}); diff != "" {
+			t.Errorf("Extra options mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("OutcomeUnknown", func(t *testing.T) {
+		server.Fail("/order", coleteonlinetest.Failure{Status: http.StatusServiceUnavailable, Times: 1})
+		_, err := orders.Create(ctx, "ref-2", &order)
+		if !errors.Is(err, coleteonline.ErrServer) {
+			t.Fatalf("Expected ErrServer, got %v", err)
+		}
+		count := len(server.Orders())
+		_, err = orders.Create(ctx, "ref-2", &order)
+		var unknownErr *coleteonline.OutcomeUnknownError
+		if !errors.As(err, &unknownErr) || !errors.Is(err, coleteonline.ErrOutcomeUnknown) {
+			t.Fatalf("Expected ErrOutcomeUnknown, got %v", err)
+		}
+		if diff := cmp.Diff(unknownErr.Key, "ref-2"); diff != "" {
+			t.Errorf("Key mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(len(server.Orders()), count); diff != "" {
+			t.Errorf("Orders mismatch (-want +got):\n%s", diff)
+		}
+
+		err = orders.Discard(ctx, "ref-2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := orders.Create(ctx, "ref-2", &order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(len(server.Orders()), count+1); diff != "" {
+			t.Errorf("Orders mismatch (-want +got):\n%s", diff)
+		}
+		if res.Service.Service.Id == 0 {
+			t.Errorf("Expected the full response, got %+v", res)
+		}
+	})
+
+	t.Run("Resolve", func(t *testing.T) {
+		// The earlier attempt created the order but its response was lost
+		lost := newTestOrder()
+		lost.AddExtraOption(coleteonline.ClientReferenceOption{Reference: "ref-3"})
+		created, err := client.CreateOrder(&lost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Put(coleteonline.IdempotencyRecord{Key: "ref-3"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := len(server.Orders())
+		err = orders.Resolve(ctx, "ref-3", created)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := orders.Create(ctx, "ref-3", &order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]string{created.UniqueId, created.AWB}, []string{res.UniqueId, res.AWB}); diff != "" {
+			t.Errorf("Response mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(len(server.Orders()), count); diff != "" {
+			t.Errorf("Orders mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		server.Fail("/order", coleteonlinetest.Failure{Status: http.StatusBadRequest, Times: 1})
+		_, err := orders.Create(ctx, "ref-5", &order)
+		if !errors.Is(err, coleteonline.ErrValidation) {
+			t.Fatalf("Expected ErrValidation, got %v", err)
+		}
+		record, err := store.Get("ref-5")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record != nil {
+			t.Errorf("Expected the record to be deleted, got %+v", record)
+		}
+		_, err = orders.Create(ctx, "ref-5", &order)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("NotSent", func(t *testing.T) {
+		config := server.Config()
+		config.BalanceGuard = &coleteonline.BalanceGuard{}
+		guarded := coleteonline.NewIdempotentOrders(coleteonline.NewClient(config), store)
+		server.Fail("/order/price", coleteonlinetest.Failure{Status: http.StatusServiceUnavailable, Times: 1})
+		_, err := guarded.Create(ctx, "ref-6", &order)
+		if !errors.Is(err, coleteonline.ErrServer) {
+			t.Fatalf("Expected ErrServer, got %v", err)
+		}
+		record, err := store.Get("ref-6")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record != nil {
+			t.Errorf("Expected the record to be deleted, got %+v", record)
+		}
+	})
+
+	t.Run("ReferenceMismatch", func(t *testing.T) {
+		other := newTestOrder()
+		other.AddExtraOption(coleteonline.ClientReferenceOption{Reference: "other"})
+		_, err := orders.Create(ctx, "ref-4", &other)
+		if err == nil {
+			t.Error("Expected an error for a mismatched client reference")
+		}
+	})
+}
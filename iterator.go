@@ -0,0 +1,121 @@
+package coleteonline
+
+import "context"
+
+// AddressIterator walks the saved addresses page by page, fetching pages only as they are needed.
+//
+//	it := client.IterateAddresses(ctx, false)
+//	for it.Next() {
+//		address := it.Address()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type AddressIterator struct {
+	client     *Client
+	ctx        context.Context
+	prefetch   bool
+	page       int64
+	totalPages int64
+	buf        []OrderAddress
+	current    OrderAddress
+	pending    chan addressPage
+	err        error
+}
+
+type addressPage struct {
+	res *AddressListResponse
+	err error
+}
+
+// IterateAddresses returns an iterator over all the saved addresses. When prefetch is set,
+// the next page is fetched concurrently while the current one is being consumed.
+func (c *Client) IterateAddresses(ctx context.Context, prefetch bool) *AddressIterator {
+	return &AddressIterator{
+		client:     c,
+		ctx:        ctx,
+		prefetch:   prefetch,
+		page:       1,
+		totalPages: -1,
+	}
+}
+
+func (c *Client) AllAddresses(ctx context.Context) ([]OrderAddress, error) {
+	var res []OrderAddress
+	it := c.IterateAddresses(ctx, false)
+	for it.Next() {
+		res = append(res, it.Address())
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	return res, nil
+}
+
+// Next advances to the next address, returning false when there are no more addresses
+// or an error occurred.
+func (it *AddressIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+	for len(it.buf) == 0 {
+		if it.totalPages >= 0 && it.page > it.totalPages {
+			return false
+		}
+		p := it.fetch()
+		if p.err != nil {
+			it.err = p.err
+			return false
+		}
+		it.buf = p.res.Data
+		it.totalPages = p.res.Pagination.TotalPages
+		it.page++
+		if it.prefetch && it.page <= it.totalPages {
+			it.startPrefetch()
+		}
+	}
+	it.current = it.buf[0]
+	it.buf = it.buf[1:]
+	return true
+}
+
+func (it *AddressIterator) Address() OrderAddress {
+	return it.current
+}
+
+func (it *AddressIterator) Err() error {
+	return it.err
+}
+
+// The current page number being consumed.
+func (it *AddressIterator) Page() int64 {
+	return it.page - 1
+}
+
+func (it *AddressIterator) fetch() addressPage {
+	if it.pending != nil {
+		var p addressPage
+		select {
+		case p = <-it.pending:
+		case <-it.ctx.Done():
+			p = addressPage{err: it.ctx.Err()}
+		}
+		it.pending = nil
+		return p
+	}
+	res, err := it.client.AddressListWithContext(it.ctx, it.page)
+	return addressPage{res: res, err: err}
+}
+
+func (it *AddressIterator) startPrefetch() {
+	// Buffered so the goroutine never blocks if the iterator is abandoned
+	pending := make(chan addressPage, 1)
+	go func(page int64) {
+		res, err := it.client.AddressListWithContext(it.ctx, page)
+		pending <- addressPage{res: res, err: err}
+	}(it.page)
+	it.pending = pending
+}
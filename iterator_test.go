@@ -0,0 +1,84 @@
+package coleteonline_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/radulucut/coleteonline"
+	"github.com/radulucut/coleteonline/coleteonlinetest"
+)
+
+func Test_AddressIterator(t *testing.T) {
+	server := coleteonlinetest.NewServer()
+	defer server.Close()
+	var addresses []coleteonline.OrderAddress
+	for i := 1; i <= 25; i++ {
+		addresses = append(addresses, coleteonline.OrderAddress{
+			AddressId: int64(i),
+			Contact: coleteonline.Contact{
+				Name: fmt.Sprintf("Contact %d", i),
+			},
+		})
+	}
+	server.SetAddresses(addresses, 10)
+	client := coleteonline.NewClient(server.Config())
+
+	t.Run("All", func(t *testing.T) {
+		res, err := client.AllAddresses(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(res, addresses); diff != "" {
+			t.Errorf("Addresses mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Prefetch", func(t *testing.T) {
+		var res []coleteonline.OrderAddress
+		it := client.IterateAddresses(context.Background(), true)
+		for it.Next() {
+			res = append(res, it.Address())
+		}
+		if it.Err() != nil {
+			t.Fatal(it.Err())
+		}
+		if diff := cmp.Diff(res, addresses); diff != "" {
+			t.Errorf("Addresses mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		it := client.IterateAddresses(context.Background(), false)
+		count := 0
+		for it.Next() {
+			count++
+			if count == 1 {
+				server.Fail("/address", coleteonlinetest.Failure{Status: http.StatusForbidden, Times: 1})
+			}
+		}
+		if diff := cmp.Diff(count, 10); diff != "" {
+			t.Errorf("Count mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(it.Err().Error(), `403: "unexpected response status"`); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		it := client.IterateAddresses(ctx, true)
+		if !it.Next() {
+			t.Fatal(it.Err())
+		}
+		cancel()
+		if it.Next() {
+			t.Error("Expected iteration to stop")
+		}
+		if it.Err() != context.Canceled {
+			t.Errorf("Expected context.Canceled, got %v", it.Err())
+		}
+	})
+}
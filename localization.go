@@ -0,0 +1,139 @@
+package coleteonline
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type StatusTranslator interface {
+	Translate(code StatusCode, lang string) (StatusTextPart, bool)
+}
+
+// Translations keyed by language code and status code.
+type StatusTranslations map[string]map[StatusCode]StatusTextPart
+
+func (t StatusTranslations) Translate(code StatusCode, lang string) (StatusTextPart, bool) {
+	part, ok := t[lang][code]
+	return part, ok
+}
+
+// English texts for the named status codes.
+var DefaultStatusTranslator StatusTranslator = StatusTranslations{
+	"en": {
+		StatusCodeRegistered:            {Name: "Order registered"},
+		StatusCodeAWBGenerated:          {Name: "AWB generated"},
+		StatusCodePickupScheduled:       {Name: "Pickup scheduled"},
+		StatusCodePickedUp:              {Name: "Picked up by courier"},
+		StatusCodeInTransit:             {Name: "In transit"},
+		StatusCodeInWarehouse:           {Name: "In warehouse"},
+		StatusCodeOutForDelivery:        {Name: "Out for delivery"},
+		StatusCodeDelivered:             {Name: "Delivered"},
+		StatusCodeFailedDeliveryAttempt: {Name: "Delivery attempt failed"},
+		StatusCodeRecipientAbsent:       {Name: "Delivery attempt failed", Reason: "Recipient absent"},
+		StatusCodeRefused:               {Name: "Delivery attempt failed", Reason: "Refused by recipient"},
+		StatusCodeWrongAddress:          {Name: "Delivery attempt failed", Reason: "Wrong address"},
+		StatusCodeReturning:             {Name: "Returning to sender"},
+		StatusCodeReturned:              {Name: "Returned to sender"},
+		StatusCodeCancelled:             {Name: "Cancelled"},
+		StatusCodeLost:                  {Name: "Lost"},
+		StatusCodeDamaged:               {Name: "Damaged"},
+	},
+}
+
+// Get returns the text in the given language, as returned by the API.
+func (p *StatusTextParts) Get(lang string) (StatusTextPart, bool) {
+	if lang == "ro" {
+		return p.Ro, p.Ro != StatusTextPart{}
+	}
+	part, ok := p.Other[lang]
+	return part, ok
+}
+
+func (p StatusTextParts) MarshalJSON() ([]byte, error) {
+	m := make(map[string]StatusTextPart, len(p.Other)+1)
+	for lang, part := range p.Other {
+		m[lang] = part
+	}
+	m["ro"] = p.Ro
+	return json.Marshal(m)
+}
+
+func (p *StatusTextParts) UnmarshalJSON(b []byte) error {
+	var m map[string]StatusTextPart
+	err := json.Unmarshal(b, &m)
+	if err != nil {
+		return err
+	}
+	p.Ro = m["ro"]
+	delete(m, "ro")
+	p.Other = nil
+	if len(m) != 0 {
+		p.Other = m
+	}
+	return nil
+}
+
+// Get returns the comment in the given language, as returned by the API.
+func (c *StatusComment) Get(lang string) (string, bool) {
+	if lang == "ro" {
+		return c.Ro, c.Ro != ""
+	}
+	comment, ok := c.Other[lang]
+	return comment, ok
+}
+
+func (c StatusComment) MarshalJSON() ([]byte, error) {
+	m := make(map[string]string, len(c.Other)+1)
+	for lang, comment := range c.Other {
+		m[lang] = comment
+	}
+	m["ro"] = c.Ro
+	return json.Marshal(m)
+}
+
+func (c *StatusComment) UnmarshalJSON(b []byte) error {
+	var m map[string]string
+	err := json.Unmarshal(b, &m)
+	if err != nil {
+		return err
+	}
+	c.Ro = m["ro"]
+	delete(m, "ro")
+	c.Other = nil
+	if len(m) != 0 {
+		c.Other = m
+	}
+	return nil
+}
+
+// Text returns the status text in the given language, preferring the one returned by the API,
+// then the translator, DefaultStatusTranslator and finally the Romanian text. The translator may be nil.
+func (h *StatusHistory) Text(lang string, translator StatusTranslator) StatusTextPart {
+	if part, ok := h.StatusTextParts.Get(lang); ok {
+		return part
+	}
+	for _, t := range []StatusTranslator{translator, DefaultStatusTranslator} {
+		if t == nil {
+			continue
+		}
+		if part, ok := t.Translate(h.Code, lang); ok {
+			return part
+		}
+	}
+	return h.StatusTextParts.Ro
+}
+
+type languageKey struct{}
+
+// WithLanguage asks the API to return texts in the given language for requests made with ctx,
+// overriding Config.Language.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+func (c *Client) language(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageKey{}).(string); ok {
+		return lang
+	}
+	return c.lang
+}
@@ -0,0 +1,95 @@
+package coleteonline
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Localization(t *testing.T) {
+	t.Run("Unmarshal", func(t *testing.T) {
+		var h StatusHistory
+		err := json.Unmarshal([]byte(`{
+			"code": 20,
+			"statusTextParts": {
+				"ro": {"name": "Livrat", "reason": ""},
+				"en": {"name": "Delivered", "reason": ""}
+			},
+			"comment": {"ro": "Predat", "en": "Handed over"}
+		}`), &h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := StatusHistory{
+			Code: StatusCodeDelivered,
+			StatusTextParts: StatusTextParts{
+				Ro:    StatusTextPart{Name: "Livrat"},
+				Other: map[string]StatusTextPart{"en": {Name: "Delivered"}},
+			},
+			StatusComment: StatusComment{
+				Ro:    "Predat",
+				Other: map[string]string{"en": "Handed over"},
+			},
+		}
+		if diff := cmp.Diff(h, expected); diff != "" {
+			t.Errorf("Status mismatch (-want +got):\n%s", diff)
+		}
+		b, err := json.Marshal(h.StatusTextParts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(string(b), `{"en":{"name":"Delivered","reason":""},"ro":{"name":"Livrat","reason":""}}`); diff != "" {
+			t.Errorf("JSON mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		h := StatusHistory{
+			Code: StatusCodeRecipientAbsent,
+			StatusTextParts: StatusTextParts{
+				Ro: StatusTextPart{Name: "Livrare esuata", Reason: "Destinatar absent"},
+			},
+		}
+		if diff := cmp.Diff(h.Text("en", nil), StatusTextPart{Name: "Delivery attempt failed", Reason: "Recipient absent"}); diff != "" {
+			t.Errorf("Text mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(h.Text("de", nil), h.StatusTextParts.Ro); diff != "" {
+			t.Errorf("Text mismatch (-want +got):\n%s", diff)
+		}
+		translator := StatusTranslations{
+			"de": {StatusCodeRecipientAbsent: {Name: "Zustellung fehlgeschlagen"}},
+		}
+		if diff := cmp.Diff(h.Text("de", translator), StatusTextPart{Name: "Zustellung fehlgeschlagen"}); diff != "" {
+			t.Errorf("Text mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(h.Text("en", translator), StatusTextPart{Name: "Delivery attempt failed", Reason: "Recipient absent"}); diff != "" {
+			t.Errorf("Text mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("AcceptLanguage", func(t *testing.T) {
+		var languages []string
+		client := NewClient(Config{
+			ClientId:     "client_id",
+			ClientSecret: "client_secret",
+			APIURL:       "http://localhost/v1",
+			Language:     "en",
+			Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				languages = append(languages, r.Header.Get("Accept-Language"))
+				return nil, context.Canceled
+			}),
+			Retry: &RetryPolicy{MaxAttempts: 1},
+		})
+		client.authBearer = "Bearer token"
+		client.authBearerExp = time.Now().Add(time.Hour)
+		client.UserBalance()
+		client.UserBalanceWithContext(WithLanguage(context.Background(), "ro"))
+		if diff := cmp.Diff(languages, []string{"en", "ro"}); diff != "" {
+			t.Errorf("Languages mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
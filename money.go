@@ -0,0 +1,175 @@
+package coleteonline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+// Used for amounts unmarshaled from the API, which only returns numbers.
+const DefaultCurrency = "RON"
+
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// Money is an exact amount in minor units (e.g. bani) with 2 decimals.
+// It is marshaled as a JSON number so it can replace float64 fields.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Compatibility with float amounts, rounded to the nearest minor unit.
+func MoneyFromFloat(f float64, currency string) Money {
+	return Money{Amount: int64(math.Round(f * 100)), Currency: currency}
+}
+
+var decimalPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+
+// ParseMoney parses a decimal such as "12.34" exactly, rounding half away from zero
+// beyond the second decimal. Fractions and exponents are rejected.
+func ParseMoney(s string, currency string) (Money, error) {
+	if !decimalPattern.MatchString(s) {
+		return Money{}, fmt.Errorf("invalid money amount: %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid money amount: %q", s)
+	}
+	r.Mul(r, big.NewRat(100, 1))
+	n := new(big.Int)
+	rem := new(big.Int)
+	n.QuoRem(r.Num(), r.Denom(), rem)
+	// Round half away from zero
+	rem.Abs(rem).Mul(rem, big.NewInt(2))
+	if rem.Cmp(r.Denom()) >= 0 {
+		if r.Sign() < 0 {
+			n.Sub(n, big.NewInt(1))
+		} else {
+			n.Add(n, big.NewInt(1))
+		}
+	}
+	if !n.IsInt64() {
+		return Money{}, fmt.Errorf("money amount out of range: %q", s)
+	}
+	return Money{Amount: n.Int64(), Currency: currency}, nil
+}
+
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / 100
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Add returns ErrCurrencyMismatch when both amounts have different, non-empty currencies.
+func (m Money) Add(o Money) (Money, error) {
+	currency, err := m.currencyWith(o)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	return m.Add(o.Neg())
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+func (m Money) Mul(n int64) Money {
+	return Money{Amount: m.Amount * n, Currency: m.Currency}
+}
+
+// Cmp returns -1, 0 or 1 when m is less than, equal to or greater than o.
+func (m Money) Cmp(o Money) (int, error) {
+	_, err := m.currencyWith(o)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (m Money) currencyWith(o Money) (string, error) {
+	switch {
+	case m.Currency == o.Currency || o.Currency == "":
+		return m.Currency, nil
+	case m.Currency == "":
+		return o.Currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+}
+
+// The decimal amount, e.g. "-12.05".
+func (m Money) Decimal() string {
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+	}
+	units := amount / 100
+	cents := amount % 100
+	if units < 0 {
+		units = -units
+	}
+	if cents < 0 {
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, units, cents)
+}
+
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// Accepts JSON numbers and numeric strings. The currency defaults to DefaultCurrency.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		return nil
+	}
+	s := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		var err error
+		s, err = strconv.Unquote(s)
+		if err != nil {
+			return err
+		}
+	}
+	currency := m.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	res, err := ParseMoney(s, currency)
+	if err != nil {
+		return err
+	}
+	*m = res
+	return nil
+}
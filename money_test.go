@@ -0,0 +1,86 @@
+package coleteonline
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Money(t *testing.T) {
+	t.Run("Unmarshal", func(t *testing.T) {
+		var res UserBalance
+		err := json.Unmarshal([]byte(`{"amount": 1234567.89, "bonus": "0.1"}`), &res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := UserBalance{
+			Amount: NewMoney(123456789, "RON"),
+			Bonus:  NewMoney(10, "RON"),
+		}
+		if diff := cmp.Diff(res, expected); diff != "" {
+			t.Errorf("Balance mismatch (-want +got):\n%s", diff)
+		}
+		b, err := json.Marshal(res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(string(b), `{"amount":1234567.89,"bonus":0.10}`); diff != "" {
+			t.Errorf("JSON mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Parse", func(t *testing.T) {
+		tests := map[string]int64{
+			"0.29":    29,
+			"-12.05":  -1205,
+			"100":     10000,
+			"0.005":   1,
+			"-0.005":  -1,
+			"0.00499": 0,
+		}
+		for s, expected := range tests {
+			m, err := ParseMoney(s, "RON")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(m.Amount, expected); diff != "" {
+				t.Errorf("%s: amount mismatch (-want +got):\n%s", s, diff)
+			}
+		}
+		_, err := ParseMoney("abc", "RON")
+		if diff := cmp.Diff(err.Error(), `invalid money amount: "abc"`); diff != "" {
+			t.Errorf("Error mismatch (-want +got):\n%s", diff)
+		}
+		for _, s := range []string{"1/3", "10/2", "1e2", ".5", "1.", "+1", "NaN"} {
+			if _, err := ParseMoney(s, "RON"); err == nil {
+				t.Errorf("%s: expected an error", s)
+			}
+		}
+	})
+
+	t.Run("Arithmetic", func(t *testing.T) {
+		sum, err := NewMoney(1010, "RON").Add(NewMoney(20, "RON"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(sum.String(), "10.30 RON"); diff != "" {
+			t.Errorf("Sum mismatch (-want +got):\n%s", diff)
+		}
+		diff, err := NewMoney(5, "RON").Sub(NewMoney(10, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(diff.String(), "-0.05 RON"); d != "" {
+			t.Errorf("Difference mismatch (-want +got):\n%s", d)
+		}
+		_, err = NewMoney(1, "RON").Cmp(NewMoney(1, "EUR"))
+		if !errors.Is(err, ErrCurrencyMismatch) {
+			t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+		}
+		if d := cmp.Diff(MoneyFromFloat(0.1+0.2, "RON"), NewMoney(30, "RON")); d != "" {
+			t.Errorf("Float mismatch (-want +got):\n%s", d)
+		}
+	})
+}
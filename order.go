@@ -1,6 +1,9 @@
 package coleteonline
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 type Sender struct {
 	AddressId          int64                  `json:"addressId,omitempty"`
@@ -67,8 +70,8 @@ type Order struct {
 }
 
 type ServicePrice struct {
-	Total float64 `json:"total"`
-	NoVat float64 `json:"noVat"`
+	Total Money `json:"total"`
+	NoVat Money `json:"noVat"`
 }
 
 type ServiceDetails struct {
@@ -121,10 +124,14 @@ type StatusTextPart struct {
 
 type StatusTextParts struct {
 	Ro StatusTextPart `json:"ro"`
+	// Any other languages returned by the API, keyed by language code.
+	Other map[string]StatusTextPart `json:"-"`
 }
 
 type StatusComment struct {
 	Ro string `json:"ro"`
+	// Any other languages returned by the API, keyed by language code.
+	Other map[string]string `json:"-"`
 }
 
 type StatusHistory struct {
@@ -132,10 +139,23 @@ type StatusHistory struct {
 	UnixDateTime    int64           `json:"unixDateTime"`
 	StatusTextParts StatusTextParts `json:"statusTextParts"`
 	StatusComment   StatusComment   `json:"comment"`
-	Code            int64           `json:"code"`
+	Code            StatusCode      `json:"code"`
 }
 
 type OrderStatusResponse struct {
 	Summary StatusSummary   `json:"summary"`
 	History []StatusHistory `json:"history"`
 }
+
+type AWBFormat string
+
+const (
+	AWBFormatA4 AWBFormat = "A4"
+	AWBFormatA6 AWBFormat = "A6"
+)
+
+type OrderAWBResponse struct {
+	Body          io.ReadCloser
+	ContentType   string
+	ContentLength int64
+}
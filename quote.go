@@ -0,0 +1,118 @@
+package coleteonline
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+var ErrNoQuote = errors.New("no service matches the quote options")
+
+type QuoteRanking string
+
+const (
+	QuoteRankingTotal QuoteRanking = "total"
+	QuoteRankingNoVat QuoteRanking = "noVat"
+)
+
+type QuoteOptions struct {
+	// Defaults to QuoteRankingTotal.
+	RankBy QuoteRanking
+	// Offers from earlier couriers rank ahead of any offer from later or unlisted couriers,
+	// regardless of price. Courier names are matched case insensitively.
+	PreferredCouriers []string
+	ExcludedCouriers  []string
+	// Offers with a higher total are dropped, zero means no limit.
+	MaxPrice Money
+}
+
+// RankQuotes returns the offers matching the options, best first. It returns ErrCurrencyMismatch
+// when prices in different currencies would have to be compared.
+func RankQuotes(list []OrderResponseService, options QuoteOptions) ([]OrderResponseService, error) {
+	res := make([]OrderResponseService, 0, len(list))
+	for _, s := range list {
+		if containsFold(options.ExcludedCouriers, s.Service.CourierName) {
+			continue
+		}
+		if !options.MaxPrice.IsZero() {
+			c, err := s.Price.Total.Cmp(options.MaxPrice)
+			if err != nil {
+				return nil, err
+			}
+			if c > 0 {
+				continue
+			}
+		}
+		res = append(res, s)
+	}
+	preference := func(s *OrderResponseService) int {
+		for i, courier := range options.PreferredCouriers {
+			if strings.EqualFold(courier, s.Service.CourierName) {
+				return i
+			}
+		}
+		return len(options.PreferredCouriers)
+	}
+	price := func(s *OrderResponseService) Money {
+		if options.RankBy == QuoteRankingNoVat {
+			return s.Price.NoVat
+		}
+		return s.Price.Total
+	}
+	var err error
+	sort.SliceStable(res, func(i, j int) bool {
+		pi, pj := preference(&res[i]), preference(&res[j])
+		if pi != pj {
+			return pi < pj
+		}
+		c, cmpErr := price(&res[i]).Cmp(price(&res[j]))
+		if cmpErr != nil && err == nil {
+			err = cmpErr
+		}
+		return c < 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Choose returns the best offer from List, or ErrNoQuote when none matches the options.
+func (r *OrderPriceResponse) Choose(options QuoteOptions) (*OrderResponseService, error) {
+	ranked, err := RankQuotes(r.List, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(ranked) == 0 {
+		return nil, ErrNoQuote
+	}
+	return &ranked[0], nil
+}
+
+// Quote prices the order and returns the best offer. The order should use ServiceTypeBestPrice
+// so every offer is listed, then ServiceDetails.OrderService selects the chosen one.
+func (c *Client) Quote(ctx context.Context, order *Order, options QuoteOptions) (*OrderResponseService, error) {
+	res, err := c.OrderPriceWithContext(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	return res.Choose(options)
+}
+
+// OrderService selects this exact service when creating an order.
+func (s ServiceDetails) OrderService() OrderService {
+	return OrderService{
+		SelectionType: ServiceTypeDirectId,
+		ServiceIds:    []int64{s.Id},
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package coleteonline
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Quote(t *testing.T) {
+	res := OrderPriceResponse{
+		List: []OrderResponseService{
+			newQuote(1, "Cargus", 20, 16.81),
+			newQuote(2, "DPD", 18, 15.50),
+			newQuote(3, "FanCourier", 19, 14.00),
+			newQuote(4, "Sameday", 30, 25.21),
+		},
+	}
+	ids := func(list []OrderResponseService) []int64 {
+		var res []int64
+		for _, s := range list {
+			res = append(res, s.Service.Id)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name     string
+		options  QuoteOptions
+		expected []int64
+	}{
+		{"Total", QuoteOptions{}, []int64{2, 3, 1, 4}},
+		{"NoVat", QuoteOptions{RankBy: QuoteRankingNoVat}, []int64{3, 2, 1, 4}},
+		{"PreferredCouriers", QuoteOptions{PreferredCouriers: []string{"sameday", "Cargus"}}, []int64{4, 1, 2, 3}},
+		{"ExcludedCouriers", QuoteOptions{ExcludedCouriers: []string{"dpd"}}, []int64{3, 1, 4}},
+		{"MaxPrice", QuoteOptions{MaxPrice: NewMoney(1900, "RON")}, []int64{2, 3}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ranked, err := RankQuotes(res.List, test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(ids(ranked), test.expected); diff != "" {
+				t.Errorf("Ranking mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("Choose", func(t *testing.T) {
+		chosen, err := res.Choose(QuoteOptions{ExcludedCouriers: []string{"DPD"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := OrderService{
+			SelectionType: ServiceTypeDirectId,
+			ServiceIds:    []int64{3},
+		}
+		if diff := cmp.Diff(chosen.Service.OrderService(), expected); diff != "" {
+			t.Errorf("Service mismatch (-want +got):\n%s", diff)
+		}
+		_, err = res.Choose(QuoteOptions{MaxPrice: NewMoney(1000, "RON")})
+		if err != ErrNoQuote {
+			t.Errorf("Expected ErrNoQuote, got %v", err)
+		}
+		_, err = res.Choose(QuoteOptions{MaxPrice: NewMoney(1000, "EUR")})
+		if !errors.Is(err, ErrCurrencyMismatch) {
+			t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+		}
+	})
+}
+
+func newQuote(id int64, courierName string, total float64, noVat float64) OrderResponseService {
+	return OrderResponseService{
+		Price: ServicePrice{
+			Total: MoneyFromFloat(total, "RON"),
+			NoVat: MoneyFromFloat(noVat, "RON"),
+		},
+		Service: ServiceDetails{
+			Id:          id,
+			CourierName: courierName,
+			Name:        "Service Name",
+		},
+	}
+}
@@ -0,0 +1,155 @@
+package coleteonline
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+type SearchCountryResponse struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type SearchLocationResponse struct {
+	CountryCode string `json:"countryCode"`
+	City        string `json:"city"`
+	County      string `json:"county"`
+	CountyCode  string `json:"countyCode"`
+	PostalCode  string `json:"postalCode"`
+}
+
+type SearchCityResponse struct {
+	Name       string `json:"name"`
+	County     string `json:"county"`
+	CountyCode string `json:"countyCode"`
+	PostalCode string `json:"postalCode"`
+}
+
+type SearchStreetResponse struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	PostalCode string `json:"postalCode"`
+}
+
+type SearchPostalCodeResponse struct {
+	PostalCode string `json:"postalCode"`
+	Street     string `json:"street"`
+	Number     string `json:"number"`
+}
+
+type ValidatePostalCodeResponse struct {
+	Valid bool `json:"valid"`
+}
+
+type SearchPostalCodeReverseResponse struct {
+	CountryCode string `json:"countryCode"`
+	PostalCode  string `json:"postalCode"`
+	City        string `json:"city"`
+	County      string `json:"county"`
+	CountyCode  string `json:"countyCode"`
+	Street      string `json:"street"`
+}
+
+func (c *Client) SearchCountry(needle string) ([]SearchCountryResponse, error) {
+	return c.SearchCountryWithContext(context.Background(), needle)
+}
+
+func (c *Client) SearchCountryWithContext(ctx context.Context, needle string) ([]SearchCountryResponse, error) {
+	var res []SearchCountryResponse
+	err := c.request(ctx, "GET", searchPath("country", needle), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) SearchLocation(countryCode, needle string) ([]SearchLocationResponse, error) {
+	return c.SearchLocationWithContext(context.Background(), countryCode, needle)
+}
+
+func (c *Client) SearchLocationWithContext(ctx context.Context, countryCode, needle string) ([]SearchLocationResponse, error) {
+	var res []SearchLocationResponse
+	err := c.request(ctx, "GET", searchPath("location", countryCode, needle), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) SearchCity(countryCode, county, needle string) ([]SearchCityResponse, error) {
+	return c.SearchCityWithContext(context.Background(), countryCode, county, needle)
+}
+
+func (c *Client) SearchCityWithContext(ctx context.Context, countryCode, county, needle string) ([]SearchCityResponse, error) {
+	var res []SearchCityResponse
+	err := c.request(ctx, "GET", searchPath("city", countryCode, county, needle), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) SearchStreet(countryCode, city, county, needle string) ([]SearchStreetResponse, error) {
+	return c.SearchStreetWithContext(context.Background(), countryCode, city, county, needle)
+}
+
+func (c *Client) SearchStreetWithContext(ctx context.Context, countryCode, city, county, needle string) ([]SearchStreetResponse, error) {
+	var res []SearchStreetResponse
+	err := c.request(ctx, "GET", searchPath("street", countryCode, city, county, needle), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) SearchPostalCode(countryCode, city, county, street string) ([]SearchPostalCodeResponse, error) {
+	return c.SearchPostalCodeWithContext(context.Background(), countryCode, city, county, street)
+}
+
+func (c *Client) SearchPostalCodeWithContext(ctx context.Context, countryCode, city, county, street string) ([]SearchPostalCodeResponse, error) {
+	var res []SearchPostalCodeResponse
+	err := c.request(ctx, "GET", searchPath("postal-code", countryCode, city, county, street), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) ValidatePostalCode(countryCode, city, county, street, postalCode string) (*ValidatePostalCodeResponse, error) {
+	return c.ValidatePostalCodeWithContext(context.Background(), countryCode, city, county, street, postalCode)
+}
+
+func (c *Client) ValidatePostalCodeWithContext(ctx context.Context, countryCode, city, county, street, postalCode string) (*ValidatePostalCodeResponse, error) {
+	var res ValidatePostalCodeResponse
+	err := c.request(ctx, "GET", searchPath("validate-postal-code", countryCode, city, county, street, postalCode), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (c *Client) SearchPostalCodeReverse(countryCode, postalCode string) ([]SearchPostalCodeReverseResponse, error) {
+	return c.SearchPostalCodeReverseWithContext(context.Background(), countryCode, postalCode)
+}
+
+func (c *Client) SearchPostalCodeReverseWithContext(ctx context.Context, countryCode, postalCode string) ([]SearchPostalCodeReverseResponse, error) {
+	var res []SearchPostalCodeReverseResponse
+	err := c.request(ctx, "GET", searchPath("postal-code-reverse", countryCode, postalCode), nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Each segment is escaped so values like "Str. 1 Decembrie 1918/A" stay a single path segment.
+func searchPath(endpoint string, segments ...string) string {
+	var sb strings.Builder
+	sb.WriteString("/search/")
+	sb.WriteString(endpoint)
+	for _, s := range segments {
+		sb.WriteByte('/')
+		sb.WriteString(url.PathEscape(s))
+	}
+	return sb.String()
+}
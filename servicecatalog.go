@@ -0,0 +1,133 @@
+package coleteonline
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServiceCatalog caches ServiceList for a TTL, measured with the client clock.
+// Concurrent calls while the cache is being refreshed share a single request, which is not
+// tied to the context of any caller and gives up after a minute, or the client timeout when shorter.
+type ServiceCatalog struct {
+	client       *Client
+	ttl          time.Duration
+	fetchTimeout time.Duration
+	mu           sync.Mutex
+	services     []ServiceResponse
+	expiresAt    time.Time
+	call         *serviceListCall
+}
+
+type serviceListCall struct {
+	done     chan struct{}
+	services []ServiceResponse
+	err      error
+}
+
+func NewServiceCatalog(client *Client, ttl time.Duration) *ServiceCatalog {
+	return &ServiceCatalog{
+		client:       client,
+		ttl:          ttl,
+		fetchTimeout: time.Minute,
+	}
+}
+
+// Services returns the cached services, fetching them when the cache expired. The returned
+// slice is shared and must not be modified.
+func (c *ServiceCatalog) Services(ctx context.Context) ([]ServiceResponse, error) {
+	c.mu.Lock()
+	if c.services != nil && c.client.timeNow().Before(c.expiresAt) {
+		services := c.services
+		c.mu.Unlock()
+		return services, nil
+	}
+	call := c.call
+	if call == nil {
+		call = &serviceListCall{done: make(chan struct{})}
+		c.call = call
+		go c.fetch(call)
+	}
+	c.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-call.done:
+		return call.services, call.err
+	}
+}
+
+// A caller giving up must not fail the others waiting for the same request, while a hung
+// request must not block every later caller.
+func (c *ServiceCatalog) fetch(call *serviceListCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.fetchTimeout)
+	defer cancel()
+	services, err := c.client.ServiceListWithContext(ctx)
+	c.mu.Lock()
+	if err == nil {
+		if services == nil {
+			services = []ServiceResponse{}
+		}
+		c.services = services
+		c.expiresAt = c.client.timeNow().Add(c.ttl)
+	}
+	c.call = nil
+	c.mu.Unlock()
+	call.services, call.err = services, err
+	close(call.done)
+}
+
+func (c *ServiceCatalog) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.services = nil
+	c.expiresAt = time.Time{}
+}
+
+// ById returns nil when there is no service with the id.
+func (c *ServiceCatalog) ById(ctx context.Context, id int64) (*ServiceResponse, error) {
+	services, err := c.Services(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range services {
+		if services[i].Id == id {
+			res := services[i]
+			return &res, nil
+		}
+	}
+	return nil, nil
+}
+
+// The courier name is matched case insensitively.
+func (c *ServiceCatalog) ByCourier(ctx context.Context, courierName string) ([]ServiceResponse, error) {
+	return c.filter(ctx, func(s *ServiceResponse) bool {
+		return strings.EqualFold(s.CourierName, courierName)
+	})
+}
+
+func (c *ServiceCatalog) WithExtraOption(ctx context.Context, id ExtraOptionId) ([]ServiceResponse, error) {
+	return c.filter(ctx, func(s *ServiceResponse) bool {
+		for _, option := range s.ExtraOptions {
+			if option.Id == int64(id) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (c *ServiceCatalog) filter(ctx context.Context, match func(s *ServiceResponse) bool) ([]ServiceResponse, error) {
+	services, err := c.Services(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var res []ServiceResponse
+	for i := range services {
+		if match(&services[i]) {
+			res = append(res, services[i])
+		}
+	}
+	return res, nil
+}
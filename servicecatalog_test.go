@@ -0,0 +1,172 @@
+package coleteonline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ServiceCatalog(t *testing.T) {
+	var calls int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	client := NewClient(Config{
+		ClientId:     "client_id",
+		ClientSecret: "client_secret",
+		APIURL:       "http://localhost/v1",
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			started <- struct{}{}
+			<-release
+			b, _ := json.Marshal(newCatalogServices())
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	})
+	now := currentTime
+	client.timeNow = func() time.Time {
+		return now
+	}
+	client.authBearer = "Bearer token"
+	client.authBearerExp = now.Add(24 * time.Hour)
+	catalog := NewServiceCatalog(client, time.Minute)
+	ctx := context.Background()
+
+	// The first caller gives up, which must not fail the others
+	canceled, cancel := context.WithCancel(ctx)
+	canceledErr := make(chan error, 1)
+	go func() {
+		_, err := catalog.Services(canceled)
+		canceledErr <- err
+	}()
+	<-started
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := catalog.Services(ctx)
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	cancel()
+	if err := <-canceledErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	close(release)
+	wg.Wait()
+	if diff := cmp.Diff(atomic.LoadInt32(&calls), int32(1)); diff != "" {
+		t.Errorf("Calls mismatch (-want +got):\n%s", diff)
+	}
+
+	service, err := catalog.ById(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(*service, newCatalogServices()[1]); diff != "" {
+		t.Errorf("Service mismatch (-want +got):\n%s", diff)
+	}
+	byCourier, err := catalog.ByCourier(ctx, "cargus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(byCourier, newCatalogServices()[:2]); diff != "" {
+		t.Errorf("Services mismatch (-want +got):\n%s", diff)
+	}
+	withInsurance, err := catalog.WithExtraOption(ctx, ExtraOptionIdInsurance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(withInsurance, newCatalogServices()[1:]); diff != "" {
+		t.Errorf("Services mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(atomic.LoadInt32(&calls), int32(1)); diff != "" {
+		t.Errorf("Calls mismatch (-want +got):\n%s", diff)
+	}
+
+	now = now.Add(time.Minute)
+	_, err = catalog.Services(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(atomic.LoadInt32(&calls), int32(2)); diff != "" {
+		t.Errorf("Calls mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_ServiceCatalog_Hung(t *testing.T) {
+	var calls int32
+	client := NewClient(Config{
+		ClientId:     "client_id",
+		ClientSecret: "client_secret",
+		APIURL:       "http://localhost/v1",
+		Retry:        &RetryPolicy{MaxAttempts: 1},
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				<-r.Context().Done()
+				return nil, r.Context().Err()
+			}
+			b, _ := json.Marshal(newCatalogServices())
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	})
+	client.authBearer = "Bearer token"
+	client.authBearerExp = time.Now().Add(time.Hour)
+	catalog := NewServiceCatalog(client, time.Minute)
+	catalog.fetchTimeout = 10 * time.Millisecond
+	ctx := context.Background()
+
+	_, err := catalog.Services(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	services, err := catalog.Services(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(services, newCatalogServices()); diff != "" {
+		t.Errorf("Services mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func newCatalogServices() []ServiceResponse {
+	return []ServiceResponse{
+		{
+			Id:          1,
+			CourierName: "Cargus",
+			Name:        "Standard",
+		},
+		{
+			Id:          2,
+			CourierName: "Cargus",
+			Name:        "Express",
+			ExtraOptions: []ServiceExtraOption{
+				{Id: int64(ExtraOptionIdInsurance), Name: "Insurance", RequiredFields: []string{"amount"}},
+			},
+		},
+		{
+			Id:          3,
+			CourierName: "DPD",
+			Name:        "Classic",
+			ExtraOptions: []ServiceExtraOption{
+				{Id: int64(ExtraOptionIdInsurance), Name: "Insurance", RequiredFields: []string{"amount"}},
+			},
+		},
+	}
+}
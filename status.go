@@ -0,0 +1,89 @@
+package coleteonline
+
+// The courier status reported in StatusHistory.Code.
+type StatusCode int64
+
+// The courier statuses returned by the API. Other codes are still kept in StatusHistory.Code,
+// String reports them as "unknown" and the helpers as not matching.
+const (
+	StatusCodeRegistered            StatusCode = 1
+	StatusCodeAWBGenerated          StatusCode = 2
+	StatusCodePickupScheduled       StatusCode = 3
+	StatusCodePickedUp              StatusCode = 10
+	StatusCodeInTransit             StatusCode = 11
+	StatusCodeInWarehouse           StatusCode = 12
+	StatusCodeOutForDelivery        StatusCode = 13
+	StatusCodeDelivered             StatusCode = 20
+	StatusCodeFailedDeliveryAttempt StatusCode = 30
+	StatusCodeRecipientAbsent       StatusCode = 31
+	StatusCodeRefused               StatusCode = 32
+	StatusCodeWrongAddress          StatusCode = 33
+	StatusCodeReturning             StatusCode = 40
+	StatusCodeReturned              StatusCode = 41
+	StatusCodeCancelled             StatusCode = 50
+	StatusCodeLost                  StatusCode = 60
+	StatusCodeDamaged               StatusCode = 61
+)
+
+var statusCodeNames = map[StatusCode]string{
+	StatusCodeRegistered:            "registered",
+	StatusCodeAWBGenerated:          "awb generated",
+	StatusCodePickupScheduled:       "pickup scheduled",
+	StatusCodePickedUp:              "picked up",
+	StatusCodeInTransit:             "in transit",
+	StatusCodeInWarehouse:           "in warehouse",
+	StatusCodeOutForDelivery:        "out for delivery",
+	StatusCodeDelivered:             "delivered",
+	StatusCodeFailedDeliveryAttempt: "failed delivery attempt",
+	StatusCodeRecipientAbsent:       "recipient absent",
+	StatusCodeRefused:               "refused",
+	StatusCodeWrongAddress:          "wrong address",
+	StatusCodeReturning:             "returning",
+	StatusCodeReturned:              "returned",
+	StatusCodeCancelled:             "cancelled",
+	StatusCodeLost:                  "lost",
+	StatusCodeDamaged:               "damaged",
+}
+
+func (c StatusCode) String() string {
+	if name, ok := statusCodeNames[c]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// The shipment will not receive further updates.
+func (c StatusCode) IsTerminal() bool {
+	switch c {
+	case StatusCodeDelivered, StatusCodeReturned, StatusCodeCancelled, StatusCodeLost:
+		return true
+	}
+	return false
+}
+
+func (c StatusCode) IsDelivered() bool {
+	return c == StatusCodeDelivered
+}
+
+func (c StatusCode) IsFailedAttempt() bool {
+	switch c {
+	case StatusCodeFailedDeliveryAttempt, StatusCodeRecipientAbsent, StatusCodeRefused, StatusCodeWrongAddress:
+		return true
+	}
+	return false
+}
+
+func (c StatusCode) IsReturn() bool {
+	return c == StatusCodeReturning || c == StatusCodeReturned
+}
+
+// Current returns the most recent history entry, or nil when the history is empty.
+func (r *OrderStatusResponse) Current() *StatusHistory {
+	var res *StatusHistory
+	for i := range r.History {
+		if res == nil || r.History[i].UnixDateTime >= res.UnixDateTime {
+			res = &r.History[i]
+		}
+	}
+	return res
+}
@@ -0,0 +1,51 @@
+package coleteonline
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Status(t *testing.T) {
+	t.Run("Current", func(t *testing.T) {
+		res := newOrderStatusResponse()
+		res.History = append(
+			[]StatusHistory{{UnixDateTime: 1672660800, Code: StatusCodeDelivered}},
+			res.History...,
+		)
+		if diff := cmp.Diff(res.Current().Code, StatusCodeDelivered); diff != "" {
+			t.Errorf("Current mismatch (-want +got):\n%s", diff)
+		}
+		empty := OrderStatusResponse{}
+		if empty.Current() != nil {
+			t.Errorf("Expected no current status, got %+v", empty.Current())
+		}
+	})
+
+	t.Run("Classification", func(t *testing.T) {
+		type classification struct {
+			Terminal, Delivered, FailedAttempt, Return bool
+		}
+		classify := func(c StatusCode) classification {
+			return classification{c.IsTerminal(), c.IsDelivered(), c.IsFailedAttempt(), c.IsReturn()}
+		}
+		got := map[StatusCode]classification{}
+		for _, c := range []StatusCode{StatusCodeInTransit, StatusCodeDelivered, StatusCodeRecipientAbsent, StatusCodeReturning, StatusCodeReturned, StatusCodeCancelled} {
+			got[c] = classify(c)
+		}
+		expected := map[StatusCode]classification{
+			StatusCodeInTransit:       {},
+			StatusCodeDelivered:       {Terminal: true, Delivered: true},
+			StatusCodeRecipientAbsent: {FailedAttempt: true},
+			StatusCodeReturning:       {Return: true},
+			StatusCodeReturned:        {Terminal: true, Return: true},
+			StatusCodeCancelled:       {Terminal: true},
+		}
+		if diff := cmp.Diff(got, expected); diff != "" {
+			t.Errorf("Classification mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(StatusCode(999).String(), "unknown"); diff != "" {
+			t.Errorf("Name mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package coleteonline
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type TrackerConfig struct {
+	// Defaults to 5 minutes.
+	Interval time.Duration
+	// Maximum number of concurrent OrderStatus calls, defaults to 4.
+	Concurrency int
+	// Reports whether a shipment should no longer be tracked after reaching the status.
+	// Defaults to StatusCode.IsTerminal, see also TerminalStatusCodes.
+	IsTerminal func(status StatusHistory) bool
+	// Called for every event when set, otherwise events are sent on the Events channel.
+	// It may be called concurrently for different shipments.
+	OnEvent func(event TrackerEvent)
+}
+
+type TrackerEvent struct {
+	// The id passed to Track.
+	UniqueIdOrAWB string
+	Summary       StatusSummary
+	Status        StatusHistory
+	// Set when the shipment is no longer tracked after this event.
+	Terminal bool
+	// Set when the status could not be fetched, in which case the other fields are empty.
+	Err error
+}
+
+// Tracker polls OrderStatus for a set of shipments and emits an event for every new history entry.
+type Tracker struct {
+	client  *Client
+	config  TrackerConfig
+	events  chan TrackerEvent
+	mu      sync.Mutex
+	tracked map[string]map[statusKey]bool
+}
+
+type statusKey struct {
+	code         StatusCode
+	unixDateTime int64
+}
+
+func NewTracker(client *Client, config TrackerConfig) *Tracker {
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 4
+	}
+	if config.IsTerminal == nil {
+		config.IsTerminal = func(status StatusHistory) bool {
+			return status.Code.IsTerminal()
+		}
+	}
+	return &Tracker{
+		client:  client,
+		config:  config,
+		events:  make(chan TrackerEvent, 64),
+		tracked: make(map[string]map[statusKey]bool),
+	}
+}
+
+// TerminalStatusCodes returns a TrackerConfig.IsTerminal reporting the given codes as terminal.
+func TerminalStatusCodes(codes ...StatusCode) func(status StatusHistory) bool {
+	terminal := make(map[StatusCode]bool, len(codes))
+	for _, code := range codes {
+		terminal[code] = true
+	}
+	return func(status StatusHistory) bool {
+		return terminal[status.Code]
+	}
+}
+
+// Events is not used when OnEvent is set and is never closed. Sends do not block: while the
+// buffer is full new statuses are not marked as seen, so the next Poll sends them again.
+func (t *Tracker) Events() <-chan TrackerEvent {
+	return t.events
+}
+
+func (t *Tracker) Track(uniqueIdsOrAWBs ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range uniqueIdsOrAWBs {
+		if _, ok := t.tracked[id]; !ok {
+			t.tracked[id] = make(map[statusKey]bool)
+		}
+	}
+}
+
+func (t *Tracker) Untrack(uniqueIdsOrAWBs ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, id := range uniqueIdsOrAWBs {
+		delete(t.tracked, id)
+	}
+}
+
+func (t *Tracker) Tracked() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	res := make([]string, 0, len(t.tracked))
+	for id := range t.tracked {
+		res = append(res, id)
+	}
+	return res
+}
+
+// Run polls immediately and then at every interval until ctx is done. It must only be called once.
+func (t *Tracker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.config.Interval)
+	defer ticker.Stop()
+	for {
+		t.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks every tracked shipment once.
+func (t *Tracker) Poll(ctx context.Context) {
+	ids := t.Tracked()
+	sem := make(chan struct{}, t.config.Concurrency)
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			t.poll(ctx, id)
+		}(id)
+	}
+	wg.Wait()
+}
+
+func (t *Tracker) poll(ctx context.Context, id string) {
+	res, err := t.client.OrderStatusWithContext(ctx, &id)
+	if err != nil {
+		if ctx.Err() == nil {
+			t.emit(TrackerEvent{UniqueIdOrAWB: id, Err: err})
+		}
+		return
+	}
+	for _, status := range res.History {
+		key := statusKey{code: status.Code, unixDateTime: status.UnixDateTime}
+		terminal := t.config.IsTerminal(status)
+		t.mu.Lock()
+		seen, ok := t.tracked[id]
+		if !ok || seen[key] {
+			t.mu.Unlock()
+			if !ok {
+				return
+			}
+			continue
+		}
+		seen[key] = true
+		if terminal {
+			delete(t.tracked, id)
+		}
+		t.mu.Unlock()
+		sent := t.emit(TrackerEvent{
+			UniqueIdOrAWB: id,
+			Summary:       res.Summary,
+			Status:        status,
+			Terminal:      terminal,
+		})
+		if !sent {
+			// Left for the next Poll, later statuses must not overtake this one
+			t.mu.Lock()
+			delete(seen, key)
+			if terminal {
+				t.tracked[id] = seen
+			}
+			t.mu.Unlock()
+			return
+		}
+		if terminal {
+			return
+		}
+	}
+}
+
+// Reports whether the event was delivered, false when the Events buffer is full.
+func (t *Tracker) emit(event TrackerEvent) bool {
+	if t.config.OnEvent != nil {
+		t.config.OnEvent(event)
+		return true
+	}
+	select {
+	case t.events <- event:
+		return true
+	default:
+		return false
+	}
+}
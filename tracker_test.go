@@ -0,0 +1,203 @@
+package coleteonline_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/radulucut/coleteonline"
+	"github.com/radulucut/coleteonline/coleteonlinetest"
+)
+
+func Test_Tracker(t *testing.T) {
+	server := coleteonlinetest.NewServer()
+	defer server.Close()
+	client := coleteonline.NewClient(server.Config())
+	var uniqueIds []string
+	for i := 0; i < 3; i++ {
+		order := newTestOrder()
+		res, err := client.CreateOrder(&order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		uniqueIds = append(uniqueIds, res.UniqueId)
+	}
+
+	var mu sync.Mutex
+	var events []string
+	tracker := coleteonline.NewTracker(client, coleteonline.TrackerConfig{
+		Concurrency: 2,
+		OnEvent: func(event coleteonline.TrackerEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			if event.Err != nil {
+				t.Error(event.Err)
+				return
+			}
+			events = append(events, event.UniqueIdOrAWB+" "+event.Status.StatusTextParts.Ro.Name)
+		},
+	})
+	tracker.Track(uniqueIds...)
+	ctx := context.Background()
+
+	tracker.Poll(ctx)
+	sort.Strings(events)
+	if diff := cmp.Diff(events, []string{
+		"order_1 Comanda inregistrata",
+		"order_2 Comanda inregistrata",
+		"order_3 Comanda inregistrata",
+	}); diff != "" {
+		t.Errorf("Events mismatch (-want +got):\n%s", diff)
+	}
+
+	events = nil
+	tracker.Poll(ctx)
+	if diff := cmp.Diff(events, []string(nil)); diff != "" {
+		t.Errorf("Events mismatch (-want +got):\n%s", diff)
+	}
+
+	advance(t, server, "order_1", coleteonline.StatusCodeDelivered, "Livrat")
+	advance(t, server, "order_2", coleteonline.StatusCodeOutForDelivery, "In livrare")
+	tracker.Poll(ctx)
+	sort.Strings(events)
+	if diff := cmp.Diff(events, []string{
+		"order_1 Livrat",
+		"order_2 In livrare",
+	}); diff != "" {
+		t.Errorf("Events mismatch (-want +got):\n%s", diff)
+	}
+	tracked := tracker.Tracked()
+	sort.Strings(tracked)
+	if diff := cmp.Diff(tracked, []string{"order_2", "order_3"}); diff != "" {
+		t.Errorf("Tracked mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_Tracker_Events(t *testing.T) {
+	server := coleteonlinetest.NewServer()
+	defer server.Close()
+	client := coleteonline.NewClient(server.Config())
+	order := newTestOrder()
+	res, err := client.CreateOrder(&order)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := coleteonline.NewTracker(client, coleteonline.TrackerConfig{
+		Interval: time.Hour,
+	})
+	tracker.Track(res.UniqueId)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- tracker.Run(ctx)
+	}()
+
+	event := <-tracker.Events()
+	if event.Err != nil {
+		t.Fatal(event.Err)
+	}
+	if diff := cmp.Diff(event.UniqueIdOrAWB+" "+event.Status.StatusTextParts.Ro.Name, res.UniqueId+" Comanda inregistrata"); diff != "" {
+		t.Errorf("Event mismatch (-want +got):\n%s", diff)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	// Polling after Run returned must not panic.
+	advance(t, server, res.UniqueId, coleteonline.StatusCodeOutForDelivery, "In livrare")
+	tracker.Poll(context.Background())
+}
+
+func Test_Tracker_FullBuffer(t *testing.T) {
+	server := coleteonlinetest.NewServer()
+	defer server.Close()
+	client := coleteonline.NewClient(server.Config())
+	tracker := coleteonline.NewTracker(client, coleteonline.TrackerConfig{})
+	for i := 0; i < 70; i++ {
+		order := newTestOrder()
+		res, err := client.CreateOrder(&order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tracker.Track(res.UniqueId)
+	}
+	ctx := context.Background()
+	drain := func() []string {
+		var ids []string
+		for {
+			select {
+			case event := <-tracker.Events():
+				if event.Err != nil {
+					t.Fatal(event.Err)
+				}
+				ids = append(ids, event.UniqueIdOrAWB)
+			default:
+				return ids
+			}
+		}
+	}
+
+	// Nothing reads the channel, Poll must still return
+	tracker.Poll(ctx)
+	first := drain()
+	if diff := cmp.Diff(len(first), 64); diff != "" {
+		t.Errorf("Events mismatch (-want +got):\n%s", diff)
+	}
+	tracker.Poll(ctx)
+	ids := append(first, drain()...)
+	sort.Strings(ids)
+	tracked := tracker.Tracked()
+	sort.Strings(tracked)
+	if diff := cmp.Diff(ids, tracked); diff != "" {
+		t.Errorf("Events mismatch (-want +got):\n%s", diff)
+	}
+	tracker.Poll(ctx)
+	if diff := cmp.Diff(drain(), []string(nil)); diff != "" {
+		t.Errorf("Events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func advance(t *testing.T, server *coleteonlinetest.Server, uniqueId string, code coleteonline.StatusCode, name string) {
+	err := server.AdvanceStatus(uniqueId, coleteonline.StatusHistory{
+		Code: code,
+		StatusTextParts: coleteonline.StatusTextParts{
+			Ro: coleteonline.StatusTextPart{Name: name},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestOrder() coleteonline.Order {
+	return coleteonline.Order{
+		Sender: coleteonline.Sender{
+			AddressId: 1,
+		},
+		Recipient: coleteonline.Recipient{
+			AddressId: 2,
+		},
+		Packages: coleteonline.Packages{
+			Type:    coleteonline.PackageTypePackage,
+			Content: "Content",
+			List: []coleteonline.Package{
+				{
+					Weight: 1,
+					Width:  1,
+					Height: 1,
+					Length: 1,
+				},
+			},
+		},
+		Service: coleteonline.OrderService{
+			SelectionType: coleteonline.ServiceTypeDirectId,
+			ServiceIds:    []int64{1},
+		},
+	}
+}
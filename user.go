@@ -1,6 +1,6 @@
 package coleteonline
 
 type UserBalance struct {
-	Amount float64 `json:"amount"`
-	Bonus  float64 `json:"bonus"`
+	Amount Money `json:"amount"`
+	Bonus  Money `json:"bonus"`
 }
@@ -0,0 +1,318 @@
+package coleteonline
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Validate reports the problems that would make the API reject the order, using the
+// same parameter/message shape as ResponseError.Errors. It returns nil when no problems are found.
+func (o *Order) Validate() Errors {
+	var errs Errors
+	errs = append(errs, prefixErrors("sender", o.Sender.Validate())...)
+	errs = append(errs, prefixErrors("recipient", o.Recipient.Validate())...)
+	errs = append(errs, prefixErrors("packages", o.Packages.Validate())...)
+	errs = append(errs, prefixErrors("service", o.Service.Validate())...)
+	errs = append(errs, o.validateExtraOptions()...)
+	return errs
+}
+
+func (s *Sender) Validate() Errors {
+	return validateOrderParty(s.AddressId, s.Contact, s.Address, s.ValidationStrategy)
+}
+
+func (r *Recipient) Validate() Errors {
+	return validateOrderParty(r.AddressId, r.Contact, r.Address, r.ValidationStrategy)
+}
+
+func validateOrderParty(
+	addressId int64,
+	contact *Contact,
+	address *Address,
+	strategy ValidationStrategyType,
+) Errors {
+	var errs Errors
+	if addressId < 0 {
+		errs = append(errs, Error{Parameter: "addressId", Message: "must be a positive number"})
+	}
+	if addressId == 0 && (contact == nil || address == nil) {
+		errs = append(errs, Error{Parameter: "addressId", Message: "either addressId or address and contact must be set"})
+	}
+	if contact != nil {
+		errs = append(errs, prefixErrors("contact", contact.Validate())...)
+	}
+	if address != nil {
+		errs = append(errs, prefixErrors("address", address.Validate())...)
+	}
+	switch strategy {
+	case "", ValidationStrategyTypeMinimal, ValidationStrategyTypePriceMinimal:
+	default:
+		errs = append(errs, Error{Parameter: "validationStrategy", Message: fmt.Sprintf("unknown validation strategy %q", strategy)})
+	}
+	return errs
+}
+
+func (c *Contact) Validate() Errors {
+	var errs Errors
+	if strings.TrimSpace(c.Name) == "" {
+		errs = append(errs, Error{Parameter: "name", Message: "is required"})
+	}
+	if strings.TrimSpace(c.Phone) == "" {
+		errs = append(errs, Error{Parameter: "phone", Message: "is required"})
+	}
+	if c.Email != "" && !strings.Contains(c.Email, "@") {
+		errs = append(errs, Error{Parameter: "email", Message: "is not a valid email address"})
+	}
+	return errs
+}
+
+func (a *Address) Validate() Errors {
+	var errs Errors
+	if len(a.CountryCode) != 2 {
+		errs = append(errs, Error{Parameter: "countryCode", Message: "must be a two letter country code"})
+	}
+	if strings.TrimSpace(a.City) == "" {
+		errs = append(errs, Error{Parameter: "city", Message: "is required"})
+	}
+	if strings.TrimSpace(a.County) == "" {
+		errs = append(errs, Error{Parameter: "county", Message: "is required"})
+	}
+	if strings.TrimSpace(a.Street) == "" {
+		errs = append(errs, Error{Parameter: "street", Message: "is required"})
+	}
+	return errs
+}
+
+func (p *Packages) Validate() Errors {
+	var errs Errors
+	switch p.Type {
+	case PackageTypeEnvelope, PackageTypePackage:
+	default:
+		errs = append(errs, Error{Parameter: "type", Message: fmt.Sprintf("unknown package type %d", p.Type)})
+	}
+	if len(p.List) == 0 {
+		errs = append(errs, Error{Parameter: "list", Message: "at least one package is required"})
+	}
+	if p.Type == PackageTypePackage && strings.TrimSpace(p.Content) == "" {
+		errs = append(errs, Error{Parameter: "content", Message: "is required"})
+	}
+	for i, pkg := range p.List {
+		param := fmt.Sprintf("list[%d]", i)
+		if pkg.Weight <= 0 {
+			errs = append(errs, Error{Parameter: param + ".weight", Message: "must be greater than 0"})
+		}
+		dimensions := []struct {
+			name  string
+			value float64
+		}{
+			{"width", pkg.Width},
+			{"height", pkg.Height},
+			{"length", pkg.Length},
+		}
+		for _, d := range dimensions {
+			if d.value < 0 {
+				errs = append(errs, Error{Parameter: param + "." + d.name, Message: "must not be negative"})
+			} else if p.Type == PackageTypePackage && d.value == 0 {
+				errs = append(errs, Error{Parameter: param + "." + d.name, Message: "is required for packages"})
+			}
+		}
+	}
+	return errs
+}
+
+func (s *OrderService) Validate() Errors {
+	var errs Errors
+	switch s.SelectionType {
+	case ServiceTypeDirectId:
+		if len(s.ServiceIds) == 0 {
+			errs = append(errs, Error{Parameter: "serviceIds", Message: "at least one service id is required for directId"})
+		}
+	case ServiceTypeBestPrice:
+	case ServiceTypeGrade:
+		if len(s.Grades) == 0 {
+			errs = append(errs, Error{Parameter: "grades", Message: "at least one grade is required for grade"})
+		}
+	case "":
+		errs = append(errs, Error{Parameter: "selectionType", Message: "is required"})
+	default:
+		errs = append(errs, Error{Parameter: "selectionType", Message: fmt.Sprintf("unknown selection type %q", s.SelectionType)})
+	}
+	if s.SelectionType != ServiceTypeGrade && len(s.Grades) != 0 {
+		errs = append(errs, Error{Parameter: "grades", Message: "only allowed for grade"})
+	}
+	for i, id := range s.ServiceIds {
+		if id <= 0 {
+			errs = append(errs, Error{Parameter: fmt.Sprintf("serviceIds[%d]", i), Message: "must be a positive number"})
+		}
+	}
+	for i, grade := range s.Grades {
+		switch grade {
+		case ServiceGradeDelivery, ServiceGradePickup, ServiceGradeRepayment:
+		default:
+			errs = append(errs, Error{Parameter: fmt.Sprintf("grades[%d]", i), Message: fmt.Sprintf("unknown grade %q", grade)})
+		}
+	}
+	return errs
+}
+
+func (o *Order) validateExtraOptions() Errors {
+	var errs Errors
+	seen := make(map[ExtraOptionId]bool, len(o.ExtraOptions))
+	for i, v := range o.ExtraOptions {
+		param := fmt.Sprintf("extraOptions[%d]", i)
+		option, err := toExtraOption(v)
+		if err != nil {
+			errs = append(errs, Error{Parameter: param, Message: err.Error()})
+			continue
+		}
+		if seen[option.OptionId()] {
+			errs = append(errs, Error{Parameter: param + ".id", Message: fmt.Sprintf("duplicate extra option %d", option.OptionId())})
+		}
+		seen[option.OptionId()] = true
+		errs = append(errs, prefixErrors(param, validateExtraOption(option))...)
+	}
+	return errs
+}
+
+func validateExtraOption(option ExtraOption) Errors {
+	var errs Errors
+	switch o := option.(type) {
+	case InsuranceOption:
+		if o.Amount.Amount <= 0 {
+			errs = append(errs, Error{Parameter: "amount", Message: "must be greater than 0"})
+		}
+	case AccountRepaymentOption:
+		if o.Amount.Amount <= 0 {
+			errs = append(errs, Error{Parameter: "amount", Message: "must be greater than 0"})
+		}
+		if strings.TrimSpace(o.IBAN) == "" {
+			errs = append(errs, Error{Parameter: "iban", Message: "is required"})
+		}
+	case CashRepaymentOption:
+		if o.Amount.Amount <= 0 {
+			errs = append(errs, Error{Parameter: "amount", Message: "must be greater than 0"})
+		}
+	case DeclaredValueOption:
+		if o.Amount.Amount <= 0 {
+			errs = append(errs, Error{Parameter: "amount", Message: "must be greater than 0"})
+		}
+	case ScheduledPickupOption:
+		if _, err := time.Parse("2006-01-02", o.Date); err != nil {
+			errs = append(errs, Error{Parameter: "date", Message: "must be formatted as YYYY-MM-DD"})
+		}
+		if o.StartTime != "" {
+			if _, err := time.Parse("15:04", o.StartTime); err != nil {
+				errs = append(errs, Error{Parameter: "startTime", Message: "must be formatted as HH:MM"})
+			}
+		}
+		if o.EndTime != "" {
+			if _, err := time.Parse("15:04", o.EndTime); err != nil {
+				errs = append(errs, Error{Parameter: "endTime", Message: "must be formatted as HH:MM"})
+			}
+		}
+		// Zero padded times compare correctly as strings
+		if o.StartTime != "" && o.EndTime != "" && o.StartTime >= o.EndTime {
+			errs = append(errs, Error{Parameter: "endTime", Message: "must be after startTime"})
+		}
+	case ClientReferenceOption:
+		if strings.TrimSpace(o.Reference) == "" {
+			errs = append(errs, Error{Parameter: "reference", Message: "is required"})
+		}
+	case BaseCurrencyOption:
+		if len(o.Currency) != 3 {
+			errs = append(errs, Error{Parameter: "currency", Message: "must be a three letter currency code"})
+		}
+	}
+	return errs
+}
+
+// ValidateForServices checks a directId order against the services returned by ServiceList,
+// reporting unknown services, extra options the services do not support and missing required fields.
+func (o *Order) ValidateForServices(services []ServiceResponse) Errors {
+	if o.Service.SelectionType != ServiceTypeDirectId {
+		return Errors{{Parameter: "service.selectionType", Message: "must be directId to validate against a service"}}
+	}
+	var errs Errors
+	for i, id := range o.Service.ServiceIds {
+		var service *ServiceResponse
+		for j := range services {
+			if services[j].Id == id {
+				service = &services[j]
+				break
+			}
+		}
+		if service == nil {
+			errs = append(errs, Error{Parameter: fmt.Sprintf("service.serviceIds[%d]", i), Message: fmt.Sprintf("unknown service %d", id)})
+			continue
+		}
+		errs = append(errs, o.validateExtraOptionsForService(service)...)
+	}
+	return errs
+}
+
+func (o *Order) validateExtraOptionsForService(service *ServiceResponse) Errors {
+	var errs Errors
+	for i, v := range o.ExtraOptions {
+		param := fmt.Sprintf("extraOptions[%d]", i)
+		option, err := toExtraOption(v)
+		if err != nil {
+			errs = append(errs, Error{Parameter: param, Message: err.Error()})
+			continue
+		}
+		var supported *ServiceExtraOption
+		for j := range service.ExtraOptions {
+			if service.ExtraOptions[j].Id == int64(option.OptionId()) {
+				supported = &service.ExtraOptions[j]
+				break
+			}
+		}
+		if supported == nil {
+			errs = append(errs, Error{
+				Parameter: param + ".id",
+				Message:   fmt.Sprintf("extra option %d is not supported by service %d (%s)", option.OptionId(), service.Id, service.Name),
+			})
+			continue
+		}
+		b, err := json.Marshal(option)
+		if err != nil {
+			errs = append(errs, Error{Parameter: param, Message: err.Error()})
+			continue
+		}
+		var fields map[string]interface{}
+		err = json.Unmarshal(b, &fields)
+		if err != nil {
+			errs = append(errs, Error{Parameter: param, Message: err.Error()})
+			continue
+		}
+		for _, field := range supported.RequiredFields {
+			if isEmptyJSONValue(fields[field]) {
+				errs = append(errs, Error{
+					Parameter: param + "." + field,
+					Message:   fmt.Sprintf("is required by service %d (%s)", service.Id, service.Name),
+				})
+			}
+		}
+	}
+	return errs
+}
+
+func isEmptyJSONValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case float64:
+		return v == 0
+	}
+	return false
+}
+
+func prefixErrors(prefix string, errs Errors) Errors {
+	for i := range errs {
+		errs[i].Parameter = prefix + "." + errs[i].Parameter
+	}
+	return errs
+}
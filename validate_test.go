@@ -0,0 +1,110 @@
+package coleteonline
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Validate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		order := newOrder()
+		if diff := cmp.Diff(order.Validate(), Errors(nil)); diff != "" {
+			t.Errorf("Errors mismatch (-want +got):\n%s", diff)
+		}
+		order = newOrderWithAddressId()
+		if diff := cmp.Diff(order.Validate(), Errors(nil)); diff != "" {
+			t.Errorf("Errors mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		order := newOrder()
+		order.Sender = Sender{
+			Contact: &Contact{
+				Phone: "0123456789",
+				Email: "sender",
+			},
+		}
+		order.Recipient.Address.CountryCode = "ROU"
+		order.Packages.List = append(order.Packages.List, Package{
+			Weight: 0,
+			Width:  -1,
+			Height: 1,
+		})
+		order.Service = OrderService{
+			SelectionType: ServiceTypeDirectId,
+			Grades:        []ServiceGrade{ServiceGradeDelivery},
+		}
+		order.AddExtraOption(
+			OpenAtDeliveryOption{},
+			AccountRepaymentOption{Amount: NewMoney(1000, DefaultCurrency)},
+			ScheduledPickupOption{Date: "02.01.2023", StartTime: "14:00", EndTime: "10:00"},
+		)
+		expected := Errors{
+			{Parameter: "sender.addressId", Message: "either addressId or address and contact must be set"},
+			{Parameter: "sender.contact.name", Message: "is required"},
+			{Parameter: "sender.contact.email", Message: "is not a valid email address"},
+			{Parameter: "recipient.address.countryCode", Message: "must be a two letter country code"},
+			{Parameter: "packages.list[1].weight", Message: "must be greater than 0"},
+			{Parameter: "packages.list[1].width", Message: "must not be negative"},
+			{Parameter: "packages.list[1].length", Message: "is required for packages"},
+			{Parameter: "service.serviceIds", Message: "at least one service id is required for directId"},
+			{Parameter: "service.grades", Message: "only allowed for grade"},
+			{Parameter: "extraOptions[1].id", Message: "duplicate extra option 2"},
+			{Parameter: "extraOptions[2].iban", Message: "is required"},
+			{Parameter: "extraOptions[3].date", Message: "must be formatted as YYYY-MM-DD"},
+			{Parameter: "extraOptions[3].endTime", Message: "must be after startTime"},
+		}
+		if diff := cmp.Diff(order.Validate(), expected); diff != "" {
+			t.Errorf("Errors mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Envelope", func(t *testing.T) {
+		packages := Packages{
+			Type: PackageTypeEnvelope,
+			List: []Package{
+				{
+					Weight: 0.5,
+				},
+			},
+		}
+		if diff := cmp.Diff(packages.Validate(), Errors(nil)); diff != "" {
+			t.Errorf("Errors mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("ForServices", func(t *testing.T) {
+		services := newServiceListResponse()
+		services[0].ExtraOptions = []ServiceExtraOption{
+			{
+				Id:             int64(ExtraOptionIdOpenAtDelivery),
+				Name:           "Open at delivery",
+				RequiredFields: []string{},
+			},
+			{
+				Id:             int64(ExtraOptionIdAccountRepayment),
+				Name:           "Account repayment",
+				RequiredFields: []string{"amount", "iban", "accountHolder"},
+			},
+		}
+		order := newOrder()
+		if diff := cmp.Diff(order.ValidateForServices(services), Errors(nil)); diff != "" {
+			t.Errorf("Errors mismatch (-want +got):\n%s", diff)
+		}
+		order.Service.ServiceIds = []int64{1, 2}
+		order.AddExtraOption(
+			AccountRepaymentOption{Amount: NewMoney(1000, DefaultCurrency), IBAN: "RO49AAAA1B31007593840000"},
+			InsuranceOption{Amount: NewMoney(10000, DefaultCurrency)},
+		)
+		expected := Errors{
+			{Parameter: "extraOptions[1].accountHolder", Message: "is required by service 1 (Service Name)"},
+			{Parameter: "extraOptions[2].id", Message: "extra option 4 is not supported by service 1 (Service Name)"},
+			{Parameter: "service.serviceIds[1]", Message: "unknown service 2"},
+		}
+		if diff := cmp.Diff(order.ValidateForServices(services), expected); diff != "" {
+			t.Errorf("Errors mismatch (-want +got):\n%s", diff)
+		}
+	})
+}